@@ -0,0 +1,50 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// maxRateLimitWait caps how long a GraphQL request waits for the rate limit window to reset
+const maxRateLimitWait = 30 * time.Second
+
+// GraphQL executes a query or mutation against the GraphQL Admin API and decodes its data into v
+// GraphQL errors are returned as GraphQLErrors, after decoding any partial data.
+func (bc *Client) GraphQL(query string, variables map[string]interface{}, v interface{}) error {
+	return bc.DoGraphQL(GraphQLRequest{Query: query, Variables: variables}, v)
+}
+
+// DoGraphQL executes a GraphQL Admin API request and decodes its data into v
+// A rate limited request is retried up to MaxRetries times once the rate limit window resets.
+func (bc *Client) DoGraphQL(gqlReq GraphQLRequest, v interface{}) error {
+	b, err := json.Marshal(gqlReq)
+	if err != nil {
+		return err
+	}
+	for retries := 0; ; retries++ {
+		req := bc.getAPIRequest(http.MethodPost, "/graphql", bytes.NewReader(b))
+		err = doGraphQL(bc.HTTPClient, req, v)
+		var rateLimited *RateLimitError
+		if !errors.As(err, &rateLimited) || retries >= bc.MaxRetries {
+			return err
+		}
+		wait := rateLimited.RetryAfter
+		if wait <= 0 {
+			wait = time.Second
+		}
+		if wait > maxRateLimitWait {
+			return err
+		}
+		time.Sleep(wait)
+	}
+}
+
+// PaginateGraphQL calls fn for every edge of the connection at path in the query's data,
+// e.g. "store.locations", fetching page after page. The query must declare an $after: String
+// variable and pass it to the connection.
+func (bc *Client) PaginateGraphQL(query string, variables map[string]interface{}, path string, fn func(Edge) error) error {
+	return paginateGraphQL(bc.DoGraphQL, query, variables, path, fn)
+}
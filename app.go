@@ -1,8 +1,10 @@
 package bigcommerce
 
 import (
+	"errors"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -21,6 +23,11 @@ type App struct {
 	HTTPClient      HTTPClient
 	MaxRetries      int
 	ChannelID       int
+	// Tokens is used by ClientFor to look up the access token of a store
+	Tokens TokenStore
+
+	clientsMu sync.Mutex
+	clients   map[string]*Client
 }
 
 // New returns a new BigCommerce API object with the given hostname, client ID, and client secret
@@ -40,10 +47,43 @@ func NewApp(hostname, appClientID, appClientSecret string) *App {
 
 func (a *App) NewClient(storeHash, xAuthToken string) *Client {
 	return &Client{
-		StoreHash:  storeHash,
-		XAuthToken: xAuthToken,
-		MaxRetries: 1,
-		HTTPClient: a.HTTPClient,
-		ChannelID:  1,
+		StoreHash:       storeHash,
+		XAuthToken:      xAuthToken,
+		MaxRetries:      1,
+		HTTPClient:      a.HTTPClient,
+		ChannelID:       1,
+		AppClientID:     a.AppClientID,
+		AppClientSecret: a.AppClientSecret,
+	}
+}
+
+// ClientFor returns the API client of a store installation, using the access token from Tokens
+// Clients are cached per store; they are dropped when the app is reinstalled or uninstalled
+// through the App handlers, or by calling ForgetClient.
+func (a *App) ClientFor(storeHash string) (*Client, error) {
+	a.clientsMu.Lock()
+	defer a.clientsMu.Unlock()
+	if c, ok := a.clients[storeHash]; ok {
+		return c, nil
 	}
+	if a.Tokens == nil {
+		return nil, errors.New("app has no token store")
+	}
+	token, err := a.Tokens.LoadToken(storeHash)
+	if err != nil {
+		return nil, err
+	}
+	c := a.NewClient(storeHash, token.AccessToken)
+	if a.clients == nil {
+		a.clients = map[string]*Client{}
+	}
+	a.clients[storeHash] = c
+	return c, nil
+}
+
+// ForgetClient drops the cached client of a store, e.g. after its token was changed
+func (a *App) ForgetClient(storeHash string) {
+	a.clientsMu.Lock()
+	defer a.clientsMu.Unlock()
+	delete(a.clients, storeHash)
 }
@@ -0,0 +1,134 @@
+package bigcommerce
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+var installedHTML = `
+<html>
+<body>
+<h1>App installed</h1>
+</body>
+</html>
+`
+
+// AppCallbacks are called by the App lifecycle handlers after the request was verified
+// A callback writes the response; when it returns an error, the handler responds 500.
+// Callbacks left nil get a minimal default response.
+type AppCallbacks struct {
+	// OnInstall is called by /auth after the token was exchanged and saved
+	OnInstall func(w http.ResponseWriter, r *http.Request, ac *AuthContext) error
+	// OnLoad is called by /load when a user opens the app in the control panel
+	OnLoad func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error
+	// OnUninstall is called by /uninstall after the token was deleted
+	OnUninstall func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error
+	// OnRemoveUser is called by /remove_user when the owner revokes a user's access
+	OnRemoveUser func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error
+}
+
+// Handler returns an http.Handler serving the four app callbacks
+// /auth, /load, /uninstall and /remove_user, as registered in the BigCommerce Developer Portal.
+// Tokens are saved to and deleted from tokens, which can be nil if the app persists them in OnInstall;
+// pass the App's Tokens to use them with ClientFor.
+// Use:
+//
+//	app := bigcommerce.NewApp("app.example.com", clientID, clientSecret)
+//	http.Handle("/", app.Handler(tokens, bigcommerce.AppCallbacks{
+//		OnLoad: func(w http.ResponseWriter, r *http.Request, cr *bigcommerce.ClientRequest) error {
+//			return tmpl.Execute(w, cr)
+//		},
+//	}))
+func (bc *App) Handler(tokens TokenStore, cb AppCallbacks) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/auth", bc.AuthHandler(tokens, cb.OnInstall))
+	mux.Handle("/load", bc.LoadHandler(cb.OnLoad))
+	mux.Handle("/uninstall", bc.UninstallHandler(tokens, cb.OnUninstall))
+	mux.Handle("/remove_user", bc.RemoveUserHandler(cb.OnRemoveUser))
+	return mux
+}
+
+// AuthHandler handles the /auth callback: it exchanges the code for an access token,
+// saves it to tokens and calls onInstall
+func (bc *App) AuthHandler(tokens TokenStore, onInstall func(w http.ResponseWriter, r *http.Request, ac *AuthContext) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ac, err := bc.GetAuthContext(r.URL.Query())
+		if err != nil {
+			log.Printf("app install: %v", err)
+			http.Error(w, "Unable to install app", http.StatusUnauthorized)
+			return
+		}
+		if tokens != nil {
+			err = tokens.SaveToken(NewStoreToken(ac))
+			if err != nil {
+				log.Printf("app install %s: saving token: %v", ac.Context, err)
+				http.Error(w, "Unable to install app", http.StatusInternalServerError)
+				return
+			}
+		}
+		bc.ForgetClient(StoreHashFromContext(ac.Context))
+		if onInstall == nil {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, installedHTML)
+			return
+		}
+		appCallbackError(w, "install", ac.Context, onInstall(w, r, ac))
+	})
+}
+
+// LoadHandler handles the /load callback: it verifies signed_payload and calls onLoad
+func (bc *App) LoadHandler(onLoad func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error) http.Handler {
+	return bc.signedHandler("load", nil, onLoad)
+}
+
+// UninstallHandler handles the /uninstall callback: it verifies signed_payload,
+// deletes the store's token from tokens and calls onUninstall
+func (bc *App) UninstallHandler(tokens TokenStore, onUninstall func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error) http.Handler {
+	return bc.signedHandler("uninstall", func(cr *ClientRequest) error {
+		bc.ForgetClient(cr.StoreHash)
+		if tokens == nil {
+			return nil
+		}
+		return tokens.DeleteToken(cr.StoreHash)
+	}, onUninstall)
+}
+
+// RemoveUserHandler handles the /remove_user callback: it verifies signed_payload and calls onRemoveUser
+func (bc *App) RemoveUserHandler(onRemoveUser func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error) http.Handler {
+	return bc.signedHandler("remove_user", nil, onRemoveUser)
+}
+
+func (bc *App) signedHandler(name string, before func(cr *ClientRequest) error, cb func(w http.ResponseWriter, r *http.Request, cr *ClientRequest) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cr, err := bc.GetClientRequest(r.URL.Query())
+		if err != nil {
+			log.Printf("app %s: %v", name, err)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if before != nil {
+			err = before(cr)
+			if err != nil && err != ErrNotFound {
+				log.Printf("app %s %s: %v", name, cr.StoreHash, err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+		}
+		if cb == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		appCallbackError(w, name, cr.StoreHash, cb(w, r, cr))
+	})
+}
+
+// appCallbackError logs a callback error and responds 500
+// if the callback already wrote a response, the status can't be changed anymore
+func appCallbackError(w http.ResponseWriter, name, store string, err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("app %s %s: %v", name, store, err)
+	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+}
@@ -1,10 +1,16 @@
 package bcmiddleware
 
 import (
+	"encoding/json"
 	"fmt"
+	"html/template"
+	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/go-chi/jwtauth/v5"
 	"github.com/lestrrat-go/jwx/jwt"
 )
 
@@ -16,33 +22,79 @@ var nonAuthHTML = `
 </html>
 `
 
+var internalErrorHTML = `
+<html>
+<body>
+<h1>Internal Server Error</h1>
+</body>
+</html>
+`
+
+var globalsMu sync.RWMutex
+
+// SetNonAuthHTML sets the page the package level Authenticator responds with
+// Use an Auth instance to configure it per router instead.
 func SetNonAuthHTML(html string) {
+	globalsMu.Lock()
+	defer globalsMu.Unlock()
 	nonAuthHTML = html
 }
 
-// Authenticator is a Chi JWT Auth Middleware
+// Auth holds the configuration of the authentication and authorization middleware
+// The unauthorized, forbidden and internal error responses are chosen by the Accept header of the request:
+// JSON clients (e.g. fetch from the app's frontend) get an RFC 7807 problem document,
+// browsers get a redirect to ReloadURL when set or the HTML page otherwise.
+// Use:
+//
+//	auth := &bcmiddleware.Auth{ReloadURL: "https://login.bigcommerce.com/"}
+//	http.Handle("/api/", verifier.Handler(auth.Authenticator(apiHandler)))
+type Auth struct {
+	// UnauthorizedHTML, ForbiddenHTML and InternalErrorHTML are executed with a Problem,
+	// the package level pages are used when nil
+	UnauthorizedHTML  *template.Template
+	ForbiddenHTML     *template.Template
+	InternalErrorHTML *template.Template
+	// ReloadURL is where browsers are redirected to when unauthenticated,
+	// e.g. the app in the store control panel to get a new session from /load
+	ReloadURL string
+}
+
+// Problem is an RFC 7807 problem document
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+var defaultAuth = &Auth{}
+
+// Authenticator is a JWT Auth Middleware rejecting requests without a valid token
+// It works behind Verifier.Handler with any router, or behind jwtauth.Verifier with chi.
 // Use:
-//  var jwtAuth = jwtauth.New("HS256", []byte("secret"), nil)
-//  r.Group(func(r chi.Router) {
-// 		r.Use(jwtauth.Verifier(jwtAuth))
-// 		r.Use(bcmiddleware.Authenticator)
-//      ... rest of the routes
-//  })
+//
+//	var jwtAuth = jwtauth.New("HS256", []byte("secret"), nil)
+//	r.Group(func(r chi.Router) {
+//		r.Use(jwtauth.Verifier(jwtAuth))
+//		r.Use(bcmiddleware.Authenticator)
+//		... rest of the routes
+//	})
 func Authenticator(next http.Handler) http.Handler {
+	return defaultAuth.Authenticator(next)
+}
+
+// Authenticator is the Authenticator middleware with the configuration of a
+func (a *Auth) Authenticator(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token, _, err := jwtauth.FromContext(r.Context())
+		token, err := TokenFromContext(r.Context())
 
 		if err != nil {
-			w.Header().Set("Content-Type", "text/html")
-			w.WriteHeader(http.StatusUnauthorized)
-			fmt.Fprint(w, nonAuthHTML)
+			a.Unauthorized(w, r, err.Error())
 			return
 		}
 
 		if token == nil || jwt.Validate(token) != nil {
-			w.Header().Set("Content-Type", "text/html")
-			w.WriteHeader(http.StatusUnauthorized)
-			fmt.Fprint(w, nonAuthHTML)
+			a.Unauthorized(w, r, "invalid token")
 			return
 		}
 
@@ -50,3 +102,96 @@ func Authenticator(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// Unauthorized writes the 401 response negotiated for the request
+func (a *Auth) Unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	if a.ReloadURL != "" && wantsHTML(r) {
+		http.Redirect(w, r, a.ReloadURL, http.StatusFound)
+		return
+	}
+	globalsMu.RLock()
+	page := nonAuthHTML
+	globalsMu.RUnlock()
+	a.respond(w, r, a.UnauthorizedHTML, page, Problem{
+		Type:   "about:blank",
+		Title:  "Not Authenticated",
+		Status: http.StatusUnauthorized,
+		Detail: detail,
+	})
+}
+
+// Forbidden writes the 403 response negotiated for the request
+func (a *Auth) Forbidden(w http.ResponseWriter, r *http.Request, detail string) {
+	globalsMu.RLock()
+	page := forbiddenHTML
+	globalsMu.RUnlock()
+	a.respond(w, r, a.ForbiddenHTML, page, Problem{
+		Type:   "about:blank",
+		Title:  "Forbidden",
+		Status: http.StatusForbidden,
+		Detail: detail,
+	})
+}
+
+// InternalError writes the 500 response negotiated for the request
+// detail is sent to the client, log the underlying error instead of passing it here.
+func (a *Auth) InternalError(w http.ResponseWriter, r *http.Request, detail string) {
+	a.respond(w, r, a.InternalErrorHTML, internalErrorHTML, Problem{
+		Type:   "about:blank",
+		Title:  "Internal Server Error",
+		Status: http.StatusInternalServerError,
+		Detail: detail,
+	})
+}
+
+func (a *Auth) respond(w http.ResponseWriter, r *http.Request, tmpl *template.Template, page string, p Problem) {
+	if !wantsHTML(r) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(p.Status)
+		json.NewEncoder(w).Encode(p)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(p.Status)
+	if tmpl == nil {
+		fmt.Fprint(w, page)
+		return
+	}
+	err := tmpl.Execute(w, p)
+	if err != nil {
+		log.Printf("bcmiddleware: %s page: %v", p.Title, err)
+	}
+}
+
+// wantsHTML returns true if the request prefers HTML over JSON
+// Requests without a preference (no Accept header or only */*) are API calls.
+func wantsHTML(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return false
+	}
+	htmlQ, jsonQ := -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(qs, 64); err == nil {
+				q = v
+			}
+		}
+		switch {
+		case mt == "text/html" || mt == "application/xhtml+xml":
+			if q > htmlQ {
+				htmlQ = q
+			}
+		case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+			if q > jsonQ {
+				jsonQ = q
+			}
+		}
+	}
+	return htmlQ > 0 && htmlQ >= jsonQ
+}
@@ -0,0 +1,141 @@
+package bcmiddleware
+
+import (
+	"log"
+	"net/http"
+	"sync"
+)
+
+var forbiddenHTML = `
+<html>
+<body>
+<h1>Forbidden</h1>
+<p>Ask the store owner for access.</p>
+</body>
+</html>
+`
+
+// SetForbiddenHTML sets the page the package level authorization middleware responds with
+// Use an Auth instance to configure it per router instead.
+func SetForbiddenHTML(html string) {
+	globalsMu.Lock()
+	defer globalsMu.Unlock()
+	forbiddenHTML = html
+}
+
+// ACL decides which permissions the users of a store have in the app
+type ACL interface {
+	Allowed(storeHash string, userID int64, permission string) (bool, error)
+}
+
+// ACLFunc is a function adapter for ACL
+type ACLFunc func(storeHash string, userID int64, permission string) (bool, error)
+
+// Allowed calls f
+func (f ACLFunc) Allowed(storeHash string, userID int64, permission string) (bool, error) {
+	return f(storeHash, userID, permission)
+}
+
+// RequireOwner is a middleware that only lets the store owner through
+// It must run after Authenticator, other users get the forbidden page with 403.
+func RequireOwner(next http.Handler) http.Handler {
+	return defaultAuth.RequireOwner(next)
+}
+
+// RequireOwner is the RequireOwner middleware with the configuration of a
+func (a *Auth) RequireOwner(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := SessionFromContext(r.Context())
+		if !ok || !s.Owner {
+			a.Forbidden(w, r, "only the store owner can do this")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission returns a middleware that lets the store owner and users the ACL grants
+// permission through, e.g. RequirePermission(acl, "settings") on the settings routes
+// It must run after Authenticator, other users get the forbidden page with 403.
+func RequirePermission(acl ACL, permission string) func(http.Handler) http.Handler {
+	return defaultAuth.RequirePermission(acl, permission)
+}
+
+// RequirePermission is the RequirePermission middleware with the configuration of a
+func (a *Auth) RequirePermission(acl ACL, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, ok := SessionFromContext(r.Context())
+			if !ok {
+				a.Forbidden(w, r, "no session")
+				return
+			}
+			if !s.Owner {
+				allowed, err := acl.Allowed(s.StoreHash, s.UserID, permission)
+				if err != nil {
+					log.Printf("ACL %s user %d %s: %v", s.StoreHash, s.UserID, permission, err)
+					a.InternalError(w, r, "permission check failed")
+					return
+				}
+				if !allowed {
+					a.Forbidden(w, r, "missing permission "+permission)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MemoryACL is an in-memory ACL of permissions granted per store and user
+type MemoryACL struct {
+	mu     sync.RWMutex
+	grants map[string]map[int64]map[string]bool
+}
+
+// NewMemoryACL returns an empty MemoryACL
+func NewMemoryACL() *MemoryACL {
+	return &MemoryACL{
+		grants: map[string]map[int64]map[string]bool{},
+	}
+}
+
+// Grant gives a user of a store the permissions
+func (a *MemoryACL) Grant(storeHash string, userID int64, permissions ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	users, ok := a.grants[storeHash]
+	if !ok {
+		users = map[int64]map[string]bool{}
+		a.grants[storeHash] = users
+	}
+	perms, ok := users[userID]
+	if !ok {
+		perms = map[string]bool{}
+		users[userID] = perms
+	}
+	for _, p := range permissions {
+		perms[p] = true
+	}
+}
+
+// Revoke takes the permissions from a user of a store, all of them if none are given
+// Use it in the App's OnRemoveUser callback.
+func (a *MemoryACL) Revoke(storeHash string, userID int64, permissions ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(permissions) == 0 {
+		delete(a.grants[storeHash], userID)
+		return
+	}
+	for _, p := range permissions {
+		delete(a.grants[storeHash][userID], p)
+	}
+}
+
+// Allowed implements ACL
+func (a *MemoryACL) Allowed(storeHash string, userID int64, permission string) (bool, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.grants[storeHash][userID][permission], nil
+}
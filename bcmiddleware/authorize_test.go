@@ -0,0 +1,49 @@
+package bcmiddleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+func TestRequirePermissionACLError(t *testing.T) {
+	acl := ACLFunc(func(storeHash string, userID int64, permission string) (bool, error) {
+		return false, errors.New("db down")
+	})
+	h := (&Auth{}).RequirePermission(acl, "settings")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler called despite the ACL error")
+	}))
+	token := jwt.New()
+	token.Set(ClaimStoreHash, "abc123")
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/json", "application/problem+json"},
+		{"text/html", "text/html"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/settings", nil)
+		r.Header.Set("Accept", tt.accept)
+		r = r.WithContext(NewContext(r.Context(), token, nil))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusInternalServerError || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: got %d %s", tt.accept, w.Code, w.Header().Get("Content-Type"))
+		}
+		if tt.contentType == "application/problem+json" {
+			var p Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil || p.Status != http.StatusInternalServerError {
+				t.Errorf("unexpected problem %+v: %v", p, err)
+			}
+		} else if strings.Contains(w.Body.String(), "db down") {
+			t.Error("ACL error leaked to the client")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package bcmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gpmd/bigcommerce-api-go"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+// SessionCookieName is the cookie Verifier and jwtauth.Verifier read the token from
+const SessionCookieName = "jwt"
+
+// DefaultSessionTTL is the lifetime of a session issued by SessionIssuer
+const DefaultSessionTTL = time.Hour
+
+// Session claim names
+const (
+	ClaimStoreHash = "store_hash"
+	ClaimUserID    = "user_id"
+	ClaimUserEmail = "user_email"
+	ClaimOwner     = "owner"
+)
+
+// Session is the app user session carried by the session JWT
+type Session struct {
+	StoreHash string
+	UserID    int64
+	UserEmail string
+	Owner     bool // the user is the store owner
+	ExpiresAt time.Time
+}
+
+// User returns the session user
+func (s *Session) User() bigcommerce.UserPart {
+	return bigcommerce.UserPart{ID: s.UserID, Email: s.UserEmail}
+}
+
+// SessionIssuer mints session JWTs for users who loaded the app from the control panel
+// The cookie is SameSite=None and Secure, as the app runs in an iframe of the control panel.
+// Use:
+//
+//	keys := bcmiddleware.HMACKeys([]byte("secret"))
+//	sessions := bcmiddleware.NewSessionIssuer(keys)
+//	http.Handle("/load", app.LoadHandler(sessions.OnLoad(func(w http.ResponseWriter, r *http.Request, cr *bigcommerce.ClientRequest) error {
+//		http.Redirect(w, r, "/app", http.StatusFound)
+//		return nil
+//	})))
+type SessionIssuer struct {
+	// Keys are the keys the Verifier uses, the first one signs new sessions;
+	// with an asymmetric algorithm it must be the private key
+	Keys KeyProvider
+	TTL  time.Duration
+	// CookieName defaults to SessionCookieName, CookiePath to "/"
+	CookieName string
+	CookiePath string
+}
+
+// NewSessionIssuer returns a SessionIssuer signing with the first key and the default TTL
+func NewSessionIssuer(keys KeyProvider) *SessionIssuer {
+	return &SessionIssuer{
+		Keys: keys,
+		TTL:  DefaultSessionTTL,
+	}
+}
+
+// Issue mints a session JWT for a verified ClientRequest and sets it as cookie
+// The token is returned as well, for apps passing it on in a header where
+// third-party cookies are blocked.
+func (s *SessionIssuer) Issue(w http.ResponseWriter, cr *bigcommerce.ClientRequest) (string, error) {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	if s.Keys == nil {
+		return "", errors.New("session issuer has no keys")
+	}
+	keys := s.Keys()
+	if len(keys) == 0 {
+		return "", errors.New("session issuer has no keys")
+	}
+	now := time.Now()
+	expires := now.Add(ttl)
+	claims := map[string]interface{}{
+		jwt.SubjectKey:    "stores/" + cr.StoreHash,
+		jwt.IssuedAtKey:   now.Unix(),
+		jwt.ExpirationKey: expires.Unix(),
+		ClaimStoreHash:    cr.StoreHash,
+		ClaimUserID:       cr.User.ID,
+		ClaimUserEmail:    cr.User.Email,
+		ClaimOwner:        cr.Owner.ID != 0 && cr.User.ID == cr.Owner.ID,
+	}
+	t := jwt.New()
+	for k, v := range claims {
+		err := t.Set(k, v)
+		if err != nil {
+			return "", err
+		}
+	}
+	signed, err := jwt.Sign(t, keys[0].Algorithm, keys[0].Key)
+	if err != nil {
+		return "", err
+	}
+	token := string(signed)
+	name := s.CookieName
+	if name == "" {
+		name = SessionCookieName
+	}
+	path := s.CookiePath
+	if path == "" {
+		path = "/"
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    token,
+		Path:     path,
+		Expires:  expires,
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	})
+	return token, nil
+}
+
+// OnLoad wraps an App load callback, issuing the session before next runs
+// next can be nil, the response is then left empty with status 200
+func (s *SessionIssuer) OnLoad(next func(w http.ResponseWriter, r *http.Request, cr *bigcommerce.ClientRequest) error) func(w http.ResponseWriter, r *http.Request, cr *bigcommerce.ClientRequest) error {
+	return func(w http.ResponseWriter, r *http.Request, cr *bigcommerce.ClientRequest) error {
+		_, err := s.Issue(w, cr)
+		if err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		return next(w, r, cr)
+	}
+}
+
+// SessionFromContext returns the session of the verified token in the request context
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	token, err := TokenFromContext(ctx)
+	if err != nil {
+		return nil, false
+	}
+	claims, err := token.AsMap(ctx)
+	if err != nil {
+		return nil, false
+	}
+	return sessionFromClaims(claims, token.Expiration()), true
+}
+
+func sessionFromClaims(claims map[string]interface{}, expires time.Time) *Session {
+	s := &Session{ExpiresAt: expires}
+	s.StoreHash, _ = claims[ClaimStoreHash].(string)
+	s.UserEmail, _ = claims[ClaimUserEmail].(string)
+	s.Owner, _ = claims[ClaimOwner].(bool)
+	switch id := claims[ClaimUserID].(type) {
+	case float64:
+		s.UserID = int64(id)
+	case int64:
+		s.UserID = id
+	}
+	return s
+}
+
+// StoreHashFromContext returns the store hash of the session in the request context
+func StoreHashFromContext(ctx context.Context) string {
+	s, ok := SessionFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return s.StoreHash
+}
+
+// UserFromContext returns the user of the session in the request context
+func UserFromContext(ctx context.Context) (bigcommerce.UserPart, bool) {
+	s, ok := SessionFromContext(ctx)
+	if !ok {
+		return bigcommerce.UserPart{}, false
+	}
+	return s.User(), true
+}
@@ -0,0 +1,168 @@
+package bcmiddleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+// ErrNoToken is the context error when the request carries no token
+var ErrNoToken = errors.New("no token found")
+
+// Key is a key session tokens can be verified with
+type Key struct {
+	Algorithm jwa.SignatureAlgorithm
+	Key       interface{}
+}
+
+// KeyProvider returns the keys to verify session tokens with, tried in order
+// Return several keys to rotate them without invalidating running sessions.
+type KeyProvider func() []Key
+
+// HMACKeys returns a KeyProvider for HS256 secrets, the current secret first
+func HMACKeys(secrets ...[]byte) KeyProvider {
+	keys := make([]Key, 0, len(secrets))
+	for _, s := range secrets {
+		keys = append(keys, Key{Algorithm: jwa.HS256, Key: s})
+	}
+	return func() []Key {
+		return keys
+	}
+}
+
+// TokenExtractor returns the token of a request or "" if there is none
+type TokenExtractor func(r *http.Request) string
+
+// TokenFromHeader reads the token from the "Authorization: Bearer" header
+func TokenFromHeader(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "bearer ") {
+		return strings.TrimSpace(h[7:])
+	}
+	return ""
+}
+
+// TokenFromCookie returns a TokenExtractor reading the token from a cookie
+func TokenFromCookie(name string) TokenExtractor {
+	return func(r *http.Request) string {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return c.Value
+	}
+}
+
+// TokenFromQuery returns a TokenExtractor reading the token from a query parameter
+// It is not a default extractor: tokens in URLs end up in logs, history and Referer headers.
+func TokenFromQuery(param string) TokenExtractor {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(param)
+	}
+}
+
+// Verifier is a net/http middleware that verifies the session token of a request and
+// stores the result in the request context, for Authenticator and SessionFromContext.
+// It works with any router accepting func(http.Handler) http.Handler middleware;
+// with chi, jwtauth.Verifier can be used instead.
+// Use:
+//
+//	verifier := bcmiddleware.NewVerifier(bcmiddleware.HMACKeys([]byte("secret")))
+//	http.Handle("/api/", verifier.Handler(bcmiddleware.Authenticator(apiHandler)))
+type Verifier struct {
+	Keys KeyProvider
+	// Extractors are tried in order, defaults to the Authorization header and the SessionCookieName cookie
+	Extractors []TokenExtractor
+	// Leeway is the tolerated clock skew for exp and nbf
+	Leeway time.Duration
+}
+
+// NewVerifier returns a Verifier with the default extractors
+func NewVerifier(keys KeyProvider) *Verifier {
+	return &Verifier{
+		Keys: keys,
+		Extractors: []TokenExtractor{
+			TokenFromHeader,
+			TokenFromCookie(SessionCookieName),
+		},
+		Leeway: time.Minute,
+	}
+}
+
+// Handler verifies the token and passes the request on, valid or not
+// Put Authenticator after it to reject unauthenticated requests.
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := v.VerifyRequest(r)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), token, err)))
+	})
+}
+
+// VerifyRequest extracts and verifies the token of a request
+func (v *Verifier) VerifyRequest(r *http.Request) (jwt.Token, error) {
+	for _, extract := range v.Extractors {
+		if s := extract(r); s != "" {
+			return v.VerifyToken(s)
+		}
+	}
+	return nil, ErrNoToken
+}
+
+// VerifyToken verifies the signature with the provided keys and validates exp and nbf,
+// tokens without exp are rejected
+func (v *Verifier) VerifyToken(s string) (jwt.Token, error) {
+	if v.Keys == nil {
+		return nil, errors.New("verifier has no keys")
+	}
+	err := errors.New("verifier has no keys")
+	for _, k := range v.Keys() {
+		var token jwt.Token
+		token, err = jwt.Parse([]byte(s), jwt.WithVerify(k.Algorithm, k.Key))
+		if err != nil {
+			continue
+		}
+		err = jwt.Validate(token, jwt.WithAcceptableSkew(v.Leeway), jwt.WithRequiredClaim(jwt.ExpirationKey))
+		if err != nil {
+			return nil, err
+		}
+		return token, nil
+	}
+	return nil, err
+}
+
+type contextKey struct{}
+
+type authResult struct {
+	token jwt.Token
+	err   error
+}
+
+// NewContext returns a context carrying a verified token or the verification error
+func NewContext(ctx context.Context, token jwt.Token, err error) context.Context {
+	return context.WithValue(ctx, contextKey{}, authResult{token: token, err: err})
+}
+
+// TokenFromContext returns the token verified by Verifier, or by jwtauth.Verifier
+// when the request went through the chi middleware instead
+func TokenFromContext(ctx context.Context) (jwt.Token, error) {
+	if res, ok := ctx.Value(contextKey{}).(authResult); ok {
+		if res.err != nil {
+			return nil, res.err
+		}
+		return res.token, nil
+	}
+	token, _, err := jwtauth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, ErrNoToken
+	}
+	return token, nil
+}
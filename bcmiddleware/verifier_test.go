@@ -0,0 +1,46 @@
+package bcmiddleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gpmd/bigcommerce-api-go"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+func TestVerifier(t *testing.T) {
+	keys := HMACKeys([]byte("secret"))
+	verifier := NewVerifier(keys)
+
+	w := httptest.NewRecorder()
+	cr := &bigcommerce.ClientRequest{StoreHash: "abc123"}
+	cr.User.ID, cr.Owner.ID = 7, 7
+	token, err := NewSessionIssuer(keys).Issue(w, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	tok, err := verifier.VerifyRequest(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sessionFromClaims(tok.PrivateClaims(), tok.Expiration())
+	if s.StoreHash != "abc123" || s.UserID != 7 || !s.Owner {
+		t.Errorf("unexpected session %+v", s)
+	}
+
+	noExp := jwt.New()
+	noExp.Set(ClaimStoreHash, "abc123")
+	signed, _ := jwt.Sign(noExp, jwa.HS256, []byte("secret"))
+	if _, err := verifier.VerifyToken(string(signed)); err == nil {
+		t.Error("token without exp accepted")
+	}
+
+	r = httptest.NewRequest("GET", "/?jwt="+token, nil)
+	if _, err := verifier.VerifyRequest(r); err != ErrNoToken {
+		t.Errorf("token read from the query by default: %v", err)
+	}
+}
@@ -17,6 +17,10 @@ type Client struct {
 	MaxRetries int
 	HTTPClient HTTPClient
 	ChannelID  int
+	// AppClientID and AppClientSecret are the credentials of the app or API account,
+	// only needed to sign JWTs like the Customer Login JWT
+	AppClientID     string `json:"-"`
+	AppClientSecret string `json:"-"`
 }
 
 var ErrNoContent = errors.New("no content 204 from BigCommerce API")
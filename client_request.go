@@ -13,7 +13,15 @@ import (
 
 // GetClientRequest returns a ClientRequest object from the BigCommerce API
 // Call it with r.URL.Query() - will return BigCommerce Client Request or error
+// signed_payload_jwt is used when present, the legacy signed_payload otherwise
 func (bc *App) GetClientRequest(requestURLQuery url.Values) (*ClientRequest, error) {
+	if jwt := requestURLQuery.Get("signed_payload_jwt"); jwt != "" {
+		claims, err := bc.VerifySignedPayloadJWT(jwt)
+		if err != nil {
+			return nil, err
+		}
+		return claims.ClientRequest(), nil
+	}
 	s := requestURLQuery.Get("signed_payload")
 	decoded, err := bc.CheckSignature(s)
 	if err != nil {
@@ -27,17 +35,24 @@ func (bc *App) GetClientRequest(requestURLQuery url.Values) (*ClientRequest, err
 	return &clrq, nil
 }
 
-// CheckSignature checks the signature of the request whith SHA256 HMAC
+// CheckSignature checks the signature of the legacy signed_payload whith SHA256 HMAC
+// the payload is "{base64 JSON}.{base64 hex HMAC}"
 func (bc *App) CheckSignature(signedPayload string) ([]byte, error) {
-	ss := strings.Split(signedPayload, ".")
 	if signedPayload == "" {
 		return nil, fmt.Errorf("no signed payload")
 	}
-	decoded, err := base64.StdEncoding.DecodeString(ss[0])
+	ss := strings.Split(signedPayload, ".")
+	if len(ss) != 2 || ss[0] == "" || ss[1] == "" {
+		return nil, fmt.Errorf("malformed signed payload")
+	}
+	if bc.AppClientSecret == "" {
+		return nil, fmt.Errorf("no client secret to check signature")
+	}
+	decoded, err := decodeBase64(ss[0])
 	if err != nil {
 		return nil, fmt.Errorf("can't decode signed payload %v", err)
 	}
-	decodedSig, err := base64.StdEncoding.DecodeString(ss[1])
+	decodedSig, err := decodeBase64(ss[1])
 	if err != nil {
 		return nil, fmt.Errorf("can't decode signature %v", err)
 	}
@@ -48,3 +63,9 @@ func (bc *App) CheckSignature(signedPayload string) ([]byte, error) {
 	}
 	return decoded, nil
 }
+
+// decodeBase64 decodes standard base64 with or without padding
+// query parameters sometimes lose the trailing "=" on the way
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,133 @@
+package bigcommerce
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jws"
+)
+
+// signJWT returns an HS256 JWT of the claims
+func signJWT(t *testing.T, claims map[string]interface{}, secret string) string {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jws.Sign(payload, jwa.HS256, []byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(token)
+}
+
+// withClaim returns a copy of claims with k set to v, or removed if v is nil
+func withClaim(claims map[string]interface{}, k string, v interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(claims))
+	for key, val := range claims {
+		c[key] = val
+	}
+	if v == nil {
+		delete(c, k)
+	} else {
+		c[k] = v
+	}
+	return c
+}
+
+func TestCheckSignature(t *testing.T) {
+	app := NewApp("app.example.com", "client-id", "secret")
+	payload := []byte(`{"user":{"id":1,"email":"a@b.c"},"owner":{"id":1,"email":"a@b.c"},"context":"stores/abc123","store_hash":"abc123","timestamp":1}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	sig := base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(mac.Sum(nil))))
+	data := base64.StdEncoding.EncodeToString(payload)
+
+	tests := []struct {
+		name          string
+		signedPayload string
+		ok            bool
+	}{
+		{"valid", data + "." + sig, true},
+		{"valid without padding", base64.RawStdEncoding.EncodeToString(payload) + "." + base64.RawStdEncoding.EncodeToString([]byte(hex.EncodeToString(mac.Sum(nil)))), true},
+		{"empty", "", false},
+		{"no separator", data, false},
+		{"empty signature", data + ".", false},
+		{"empty payload", "." + sig, false},
+		{"too many parts", data + "." + sig + ".x", false},
+		{"invalid base64", "!!!." + sig, false},
+		{"wrong signature", data + "." + base64.StdEncoding.EncodeToString([]byte("00")), false},
+	}
+	for _, tt := range tests {
+		decoded, err := app.CheckSignature(tt.signedPayload)
+		if tt.ok && (err != nil || string(decoded) != string(payload)) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	if _, err := NewApp("app.example.com", "client-id", "").CheckSignature(data + "." + sig); err == nil {
+		t.Error("signature checked without a client secret")
+	}
+}
+
+func TestVerifySignedPayloadJWT(t *testing.T) {
+	app := NewApp("app.example.com", "client-id", "secret")
+	now := time.Now().Unix()
+	valid := map[string]interface{}{
+		"aud":   "client-id",
+		"iss":   "bc",
+		"iat":   now,
+		"nbf":   now - 5,
+		"exp":   now + 3600,
+		"sub":   "stores/abc123",
+		"user":  map[string]interface{}{"id": 2, "email": "user@example.com"},
+		"owner": map[string]interface{}{"id": 1, "email": "owner@example.com"},
+		"url":   "/",
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		app     *App
+		wantErr bool
+	}{
+		{"valid", signJWT(t, valid, "secret"), app, false},
+		{"audience list", signJWT(t, withClaim(valid, "aud", []string{"other", "client-id"}), "secret"), app, false},
+		{"wrong secret", signJWT(t, valid, "other"), app, true},
+		{"wrong audience", signJWT(t, withClaim(valid, "aud", "other-app"), "secret"), app, true},
+		{"no audience", signJWT(t, withClaim(valid, "aud", nil), "secret"), app, true},
+		{"wrong issuer", signJWT(t, withClaim(valid, "iss", "evil"), "secret"), app, true},
+		{"expired", signJWT(t, withClaim(valid, "exp", now-3600), "secret"), app, true},
+		{"no expiry", signJWT(t, withClaim(valid, "exp", nil), "secret"), app, true},
+		{"not valid yet", signJWT(t, withClaim(valid, "nbf", now+3600), "secret"), app, true},
+		{"no store context", signJWT(t, withClaim(valid, "sub", "abc123"), "secret"), app, true},
+		{"no subject", signJWT(t, withClaim(valid, "sub", nil), "secret"), app, true},
+		{"app without client ID", signJWT(t, withClaim(valid, "aud", ""), "secret"), NewApp("app.example.com", "", "secret"), true},
+		{"not a JWT", "abc", app, true},
+	}
+	for _, tt := range tests {
+		claims, err := tt.app.VerifySignedPayloadJWT(tt.token)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		cr := claims.ClientRequest()
+		if cr.StoreHash != "abc123" || cr.User.ID != 2 || cr.Owner.ID != 1 {
+			t.Errorf("%s: unexpected client request %+v", tt.name, cr)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentCustomer is the shopper identified by a current customer JWT
+type CurrentCustomer struct {
+	ID      int64
+	Email   string
+	GroupID int64
+}
+
+// CurrentCustomerClaims are the claims of the JWT returned by the storefront's
+// /customer/current.jwt?app_client_id={client ID} endpoint
+type CurrentCustomerClaims struct {
+	JWTClaims
+	Customer      CurrentCustomer
+	StoreHash     string
+	ApplicationID string
+	Operation     string
+	Version       int
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+// group_id is sent as a string, or empty for customers without a group
+func (c *CurrentCustomerClaims) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		JWTClaims
+		Customer struct {
+			ID      int64           `json:"id"`
+			Email   string          `json:"email"`
+			GroupID json.RawMessage `json:"group_id"`
+		} `json:"customer"`
+		StoreHash     string `json:"store_hash"`
+		ApplicationID string `json:"application_id"`
+		Operation     string `json:"operation"`
+		Version       int    `json:"version"`
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+	*c = CurrentCustomerClaims{
+		JWTClaims: raw.JWTClaims,
+		Customer: CurrentCustomer{
+			ID:    raw.Customer.ID,
+			Email: raw.Customer.Email,
+		},
+		StoreHash:     raw.StoreHash,
+		ApplicationID: raw.ApplicationID,
+		Operation:     raw.Operation,
+		Version:       raw.Version,
+	}
+	groupID := strings.Trim(string(raw.Customer.GroupID), `"`)
+	if groupID != "" && groupID != "null" {
+		c.Customer.GroupID, err = strconv.ParseInt(groupID, 10, 64)
+		if err != nil {
+			return errors.New("invalid customer group_id " + groupID)
+		}
+	}
+	return nil
+}
+
+// VerifyCurrentCustomerJWT verifies a current customer JWT fetched by a storefront script
+// with the app's client secret, its audience (the app's client ID) and expiry, and returns
+// the claims identifying the logged in shopper. Check StoreHash when the app serves several stores.
+func (bc *App) VerifyCurrentCustomerJWT(token string) (*CurrentCustomerClaims, error) {
+	if bc.AppClientID == "" {
+		return nil, errors.New("no client ID to verify token audience")
+	}
+	var claims CurrentCustomerClaims
+	err := verifyHS256(token, bc.AppClientSecret, &claims)
+	if err != nil {
+		return nil, err
+	}
+	err = claims.validate("bc/apps", bc.AppClientID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Operation != "" && claims.Operation != "current_customer" {
+		return nil, errors.New("invalid token operation " + claims.Operation)
+	}
+	if claims.Customer.ID == 0 {
+		return nil, errors.New("token has no customer")
+	}
+	if claims.StoreHash == "" {
+		claims.StoreHash = claims.Subject
+	}
+	return &claims, nil
+}
@@ -0,0 +1,58 @@
+package bigcommerce
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyCurrentCustomerJWT(t *testing.T) {
+	app := NewApp("app.example.com", "client-id", "secret")
+	now := time.Now().Unix()
+	valid := map[string]interface{}{
+		"customer":       map[string]interface{}{"id": 4927, "email": "john.doe@example.com", "group_id": "6"},
+		"iss":            "bc/apps",
+		"sub":            "abc123",
+		"iat":            now,
+		"exp":            now + 900,
+		"version":        1,
+		"aud":            "client-id",
+		"application_id": "client-id",
+		"store_hash":     "abc123",
+		"operation":      "current_customer",
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		app     *App
+		wantErr bool
+	}{
+		{"valid", signJWT(t, valid, "secret"), app, false},
+		{"wrong secret", signJWT(t, valid, "other"), app, true},
+		{"wrong audience", signJWT(t, withClaim(valid, "aud", "other-app"), "secret"), app, true},
+		{"wrong issuer", signJWT(t, withClaim(valid, "iss", "bc"), "secret"), app, true},
+		{"expired", signJWT(t, withClaim(valid, "exp", now-3600), "secret"), app, true},
+		{"no expiry", signJWT(t, withClaim(valid, "exp", nil), "secret"), app, true},
+		{"wrong operation", signJWT(t, withClaim(valid, "operation", "customer_login"), "secret"), app, true},
+		{"no customer", signJWT(t, withClaim(valid, "customer", nil), "secret"), app, true},
+		{"invalid group", signJWT(t, withClaim(valid, "customer", map[string]interface{}{"id": 1, "group_id": "x"}), "secret"), app, true},
+		{"app without client ID", signJWT(t, withClaim(valid, "aud", ""), "secret"), NewApp("app.example.com", "", "secret"), true},
+	}
+	for _, tt := range tests {
+		claims, err := tt.app.VerifyCurrentCustomerJWT(tt.token)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		c := claims.Customer
+		if c.ID != 4927 || c.Email != "john.doe@example.com" || c.GroupID != 6 || claims.StoreHash != "abc123" {
+			t.Errorf("%s: unexpected claims %+v", tt.name, claims)
+		}
+	}
+}
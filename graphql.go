@@ -0,0 +1,190 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GraphQLRequest is the body of a GraphQL request
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// GraphQLLocation is the position of a GraphQL error in the query
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an error reported in the errors list of a GraphQL response
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+	path := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		path[i] = fmt.Sprint(p)
+	}
+	return strings.Join(path, ".") + ": " + e.Message
+}
+
+// GraphQLErrors are the errors of a GraphQL response. They are returned together with
+// any partial data, which is still decoded into the caller's value.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "graphql: " + strings.Join(msgs, "; ")
+}
+
+// PageInfo is the pagination info of a GraphQL connection
+type PageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+// Edge is an edge of a GraphQL connection, Node is left raw for the caller to decode
+type Edge struct {
+	Cursor string          `json:"cursor"`
+	Node   json.RawMessage `json:"node"`
+}
+
+// Decode decodes the edge's node into v
+func (e Edge) Decode(v interface{}) error {
+	return json.Unmarshal(e.Node, v)
+}
+
+// Connection is a page of a GraphQL connection (edges and pageInfo)
+type Connection struct {
+	Edges    []Edge   `json:"edges"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+// RateLimitError is returned when BigCommerce answers 429 Too Many Requests
+type RateLimitError struct {
+	// RetryAfter is the time until the rate limit window resets, from X-Rate-Limit-Time-Reset-Ms
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return "rate limited by BigCommerce API, retry after " + e.RetryAfter.String()
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors GraphQLErrors   `json:"errors"`
+}
+
+// graphQLExecutor executes a GraphQL request and decodes its data into v
+type graphQLExecutor func(req GraphQLRequest, v interface{}) error
+
+// doGraphQL sends a GraphQL request built by the caller and decodes the response data into v
+func doGraphQL(client HTTPClient, req *http.Request, v interface{}) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusTooManyRequests {
+		ms, _ := strconv.Atoi(res.Header.Get("X-Rate-Limit-Time-Reset-Ms"))
+		return &RateLimitError{RetryAfter: time.Duration(ms) * time.Millisecond}
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	var gqlRes graphQLResponse
+	jsonErr := json.Unmarshal(body, &gqlRes)
+	if res.StatusCode > 299 && (jsonErr != nil || len(gqlRes.Errors) == 0) {
+		return fmt.Errorf("error executing graphql request: %s %s", res.Status, string(body))
+	}
+	if jsonErr != nil {
+		return fmt.Errorf("error decoding graphql response: %v %s", jsonErr, string(body))
+	}
+	if v != nil && len(gqlRes.Data) > 0 && string(gqlRes.Data) != "null" {
+		err = json.Unmarshal(gqlRes.Data, v)
+		if err != nil {
+			return err
+		}
+	}
+	if len(gqlRes.Errors) > 0 {
+		return gqlRes.Errors
+	}
+	return nil
+}
+
+// paginateGraphQL runs query once per page of the connection found at path in the response
+// data, e.g. "site.products", passing the end cursor of the previous page as $after
+func paginateGraphQL(exec graphQLExecutor, query string, variables map[string]interface{}, path string, fn func(Edge) error) error {
+	vars := map[string]interface{}{}
+	for k, v := range variables {
+		vars[k] = v
+	}
+	for {
+		var data json.RawMessage
+		err := exec(GraphQLRequest{Query: query, Variables: vars}, &data)
+		if err != nil {
+			return err
+		}
+		conn, err := connectionAt(data, path)
+		if err != nil {
+			return err
+		}
+		for _, edge := range conn.Edges {
+			err = fn(edge)
+			if err != nil {
+				return err
+			}
+		}
+		if !conn.PageInfo.HasNextPage || conn.PageInfo.EndCursor == "" {
+			return nil
+		}
+		vars["after"] = conn.PageInfo.EndCursor
+	}
+}
+
+// connectionAt returns the connection at the dot separated path of the response data
+func connectionAt(data json.RawMessage, path string) (*Connection, error) {
+	raw := data
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			continue
+		}
+		var obj map[string]json.RawMessage
+		err := json.Unmarshal(raw, &obj)
+		if err != nil || obj == nil {
+			return nil, errors.New("graphql: no connection at " + path)
+		}
+		var ok bool
+		raw, ok = obj[key]
+		if !ok {
+			return nil, errors.New("graphql: no connection at " + path)
+		}
+	}
+	var conn Connection
+	err := json.Unmarshal(raw, &conn)
+	if err != nil {
+		return nil, fmt.Errorf("graphql: invalid connection at %s: %v", path, err)
+	}
+	return &conn, nil
+}
@@ -0,0 +1,107 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jws"
+)
+
+// JWTLeeway is the clock skew tolerated when validating exp, nbf and iat of BigCommerce JWTs
+var JWTLeeway = time.Minute
+
+// ErrTokenExpired is returned for JWTs past their exp claim
+var ErrTokenExpired = errors.New("token expired")
+
+// Audience is the aud claim of a JWT, a single string or a list of strings
+type Audience []string
+
+// MarshalJSON implements json.Marshaler, a single audience is encoded as a string
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var l []string
+	err := json.Unmarshal(b, &l)
+	if err != nil {
+		return err
+	}
+	*a = l
+	return nil
+}
+
+// Contains returns true if aud is one of the audiences
+func (a Audience) Contains(aud string) bool {
+	for _, s := range a {
+		if s == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// JWTClaims are the registered claims of the JWTs BigCommerce issues and accepts
+type JWTClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// validate checks issuer and audience (when not empty) and the time claims
+func (c JWTClaims) validate(issuer, audience string, now time.Time) error {
+	if issuer != "" && c.Issuer != issuer {
+		return fmt.Errorf("invalid token issuer %q", c.Issuer)
+	}
+	if audience != "" && !c.Audience.Contains(audience) {
+		return fmt.Errorf("invalid token audience %v", []string(c.Audience))
+	}
+	if c.ExpiresAt == 0 {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(JWTLeeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Add(JWTLeeway).Before(time.Unix(c.NotBefore, 0)) {
+		return errors.New("token not valid yet")
+	}
+	if c.IssuedAt != 0 && now.Add(JWTLeeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("token issued in the future")
+	}
+	return nil
+}
+
+// verifyHS256 checks the HS256 signature of a compact JWT and decodes its claims into v
+func verifyHS256(token, secret string, v interface{}) error {
+	if token == "" {
+		return errors.New("no token")
+	}
+	if secret == "" {
+		return errors.New("no secret to verify token")
+	}
+	payload, err := jws.Verify([]byte(token), jwa.HS256, []byte(secret))
+	if err != nil {
+		return fmt.Errorf("invalid token: %v", err)
+	}
+	err = json.Unmarshal(payload, v)
+	if err != nil {
+		return fmt.Errorf("invalid token claims: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// OrderDetails is an order with all of its subresources resolved into concrete types
+type OrderDetails struct {
+	Order
+	Products          []OrderProduct         `json:"products"`
+	ShippingAddresses []OrderShippingAddress `json:"shipping_addresses"`
+	Coupons           []OrderCoupon          `json:"coupons"`
+	Shipments         []OrderShipment        `json:"shipments"`
+	Metafields        []Metafield            `json:"metafields"`
+}
+
+// OrderFormField is a custom checkout form field value on an order address
+type OrderFormField struct {
+	Name  string         `json:"name"`
+	Value FormFieldValue `json:"value"`
+}
+
+// FormFieldValue holds the value(s) of a form field
+// BigCommerce sends a single string for most field types and a list for checkbox fields
+type FormFieldValue []string
+
+// String returns the values joined by ", "
+func (v FormFieldValue) String() string {
+	s := ""
+	for i, val := range v {
+		if i > 0 {
+			s += ", "
+		}
+		s += val
+	}
+	return s
+}
+
+// MarshalJSON implements json.Marshaler, single values are encoded as a string
+func (v FormFieldValue) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepts a string, a number or a list of them
+func (v *FormFieldValue) UnmarshalJSON(b []byte) error {
+	var raw interface{}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+	switch val := raw.(type) {
+	case nil:
+		*v = nil
+	case []interface{}:
+		vals := make(FormFieldValue, 0, len(val))
+		for _, item := range val {
+			vals = append(vals, formFieldString(item))
+		}
+		*v = vals
+	default:
+		*v = FormFieldValue{formFieldString(val)}
+	}
+	return nil
+}
+
+func formFieldString(val interface{}) string {
+	switch s := val.(type) {
+	case string:
+		return s
+	case nil:
+		return ""
+	default:
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+}
+
+// OrderShipment is a shipment of an order
+type OrderShipment struct {
+	ID                   int64               `json:"id"`
+	OrderID              int64               `json:"order_id"`
+	CustomerID           int64               `json:"customer_id"`
+	OrderAddressID       int64               `json:"order_address_id"`
+	DateCreated          RFC1123Time         `json:"date_created"`
+	TrackingNumber       string              `json:"tracking_number"`
+	MerchantShippingCost string              `json:"merchant_shipping_cost"`
+	ShippingMethod       string              `json:"shipping_method"`
+	Comments             string              `json:"comments"`
+	ShippingProvider     string              `json:"shipping_provider"`
+	TrackingCarrier      string              `json:"tracking_carrier"`
+	TrackingLink         string              `json:"tracking_link"`
+	BillingAddress       OrderAddress        `json:"billing_address"`
+	ShippingAddress      OrderAddress        `json:"shipping_address"`
+	Items                []OrderShipmentItem `json:"items"`
+}
+
+// OrderShipmentItem is a line of an order shipment
+type OrderShipmentItem struct {
+	OrderProductID int64 `json:"order_product_id"`
+	ProductID      int64 `json:"product_id"`
+	Quantity       int   `json:"quantity"`
+}
+
+// GetOrderShipments returns all shipments for a given order
+func (bc *Client) GetOrderShipments(orderID int64) ([]OrderShipment, error) {
+	url := "/v2/orders/" + strconv.FormatInt(orderID, 10) + "/shipments"
+
+	req := bc.getAPIRequest(http.MethodGet, url, nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipments []OrderShipment
+	err = json.Unmarshal(body, &shipments)
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+// GetOrderMetafields returns all metafields for a given order (v3 API), reading every page
+func (bc *Client) GetOrderMetafields(orderID int64) ([]Metafield, error) {
+	metafields := []Metafield{}
+	for page := 1; ; page++ {
+		mfs, more, err := bc.getOrderMetafieldsPage(orderID, page)
+		if err != nil {
+			return nil, err
+		}
+		metafields = append(metafields, mfs...)
+		if !more {
+			return metafields, nil
+		}
+	}
+}
+
+func (bc *Client) getOrderMetafieldsPage(orderID int64, page int) ([]Metafield, bool, error) {
+	url := "/v3/orders/" + strconv.FormatInt(orderID, 10) + "/metafields?limit=250&page=" + strconv.Itoa(page)
+
+	req := bc.getAPIRequest(http.MethodGet, url, nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var metafieldsResponse struct {
+		Data []Metafield `json:"data"`
+		Meta struct {
+			Pagination Pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+	err = json.Unmarshal(body, &metafieldsResponse)
+	if err != nil {
+		return nil, false, err
+	}
+	pagination := metafieldsResponse.Meta.Pagination
+	return metafieldsResponse.Data, pagination.CurrentPage < pagination.TotalPages, nil
+}
+
+// GetOrderWithDetails returns an order with its products, shipping addresses, coupons,
+// shipments and metafields. The subresources are fetched concurrently and completely, all
+// pages of products and metafields are read; subresources without content (204) are returned
+// as empty lists.
+func (bc *Client) GetOrderWithDetails(orderID int64) (*OrderDetails, error) {
+	order, err := bc.getOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	details := OrderDetails{Order: *order}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	fetch := func(f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := f()
+			if err != nil && err != ErrNoContent {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	fetch(func() (err error) {
+		details.Products, err = bc.GetOrderProducts(orderID)
+		return err
+	})
+	fetch(func() (err error) {
+		details.ShippingAddresses, err = bc.GetOrderShippingAddresses(orderID)
+		return err
+	})
+	fetch(func() (err error) {
+		details.Coupons, err = bc.GetOrderCoupons(orderID)
+		return err
+	})
+	fetch(func() (err error) {
+		details.Shipments, err = bc.GetOrderShipments(orderID)
+		return err
+	})
+	fetch(func() (err error) {
+		details.Metafields, err = bc.GetOrderMetafields(orderID)
+		return err
+	})
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if details.Products == nil {
+		details.Products = []OrderProduct{}
+	}
+	if details.ShippingAddresses == nil {
+		details.ShippingAddresses = []OrderShippingAddress{}
+	}
+	if details.Coupons == nil {
+		details.Coupons = []OrderCoupon{}
+	}
+	if details.Shipments == nil {
+		details.Shipments = []OrderShipment{}
+	}
+	if details.Metafields == nil {
+		details.Metafields = []Metafield{}
+	}
+	details.Order.Products = details.Products
+	details.Order.ShippingAddresses = details.ShippingAddresses
+	details.Order.Coupons = details.Coupons
+	return &details, nil
+}
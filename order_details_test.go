@@ -0,0 +1,63 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetOrderWithDetailsPages(t *testing.T) {
+	client := NewClient("store", "token")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		respond := func(status int, body string) (*http.Response, error) {
+			return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(body)), Request: r}, nil
+		}
+		q := r.URL.Query()
+		page, _ := strconv.Atoi(q.Get("page"))
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/v2/orders/7"):
+			return respond(http.StatusOK, `{"id":7}`)
+		case strings.HasSuffix(r.URL.Path, "/products"):
+			// 300 products, served in pages of the requested limit
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			var products []OrderProduct
+			for i := (page-1)*limit + 1; i <= page*limit && i <= 300; i++ {
+				products = append(products, OrderProduct{ID: int64(i)})
+			}
+			if len(products) == 0 {
+				return respond(http.StatusNoContent, "")
+			}
+			b, _ := json.Marshal(products)
+			return respond(http.StatusOK, string(b))
+		case strings.HasSuffix(r.URL.Path, "/metafields"):
+			b, _ := json.Marshal(map[string]interface{}{
+				"data": []Metafield{{}},
+				"meta": map[string]interface{}{"pagination": map[string]interface{}{"current_page": page, "total_pages": 3}},
+			})
+			return respond(http.StatusOK, string(b))
+		}
+		return respond(http.StatusNoContent, "")
+	})}
+
+	details, err := client.GetOrderWithDetails(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Products) != 300 {
+		t.Errorf("got %d products, want 300", len(details.Products))
+	}
+	if len(details.Metafields) != 3 {
+		t.Errorf("got %d metafields, want 3", len(details.Metafields))
+	}
+	b, _ := json.Marshal(details)
+	var out map[string]json.RawMessage
+	json.Unmarshal(b, &out)
+	for _, k := range []string{"coupons", "shipments"} {
+		if string(out[k]) != "[]" {
+			t.Errorf("%s marshalled as %s, want []", k, out[k])
+		}
+	}
+}
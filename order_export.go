@@ -0,0 +1,393 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderExportMode selects whether an export has one row per order or per line item
+type OrderExportMode int
+
+const (
+	// ExportPerLineItem writes one row for every product of an order,
+	// orders without products get a single row with empty product columns
+	ExportPerLineItem OrderExportMode = iota
+	// ExportPerOrder writes one row for every order, products are not fetched
+	ExportPerOrder
+)
+
+// OrderExportColumn is a column of an order export
+// Product is nil when exporting per order and for orders without products
+type OrderExportColumn struct {
+	Header string
+	Value  func(o *Order, p *OrderProduct, f *StoreFormat) string
+}
+
+// OrderExportColumns are the predefined export columns by header name
+var OrderExportColumns = map[string]OrderExportColumn{}
+
+// DefaultOrderExportColumns are the columns used when OrderExporter.Columns is empty
+var DefaultOrderExportColumns = []string{
+	"order_id", "date_created", "status", "customer_id", "billing_email",
+	"currency_code", "subtotal_inc_tax", "shipping_cost_inc_tax", "total_tax", "total_inc_tax",
+	"sku", "product_name", "quantity", "price_inc_tax", "line_total_inc_tax",
+}
+
+func init() {
+	orderColumn := func(header string, value func(o *Order, f *StoreFormat) string) {
+		OrderExportColumns[header] = OrderExportColumn{
+			Header: header,
+			Value: func(o *Order, p *OrderProduct, f *StoreFormat) string {
+				return value(o, f)
+			},
+		}
+	}
+	productColumn := func(header string, value func(p *OrderProduct, f *StoreFormat) string) {
+		OrderExportColumns[header] = OrderExportColumn{
+			Header: header,
+			Value: func(o *Order, p *OrderProduct, f *StoreFormat) string {
+				if p == nil {
+					return ""
+				}
+				return value(p, f)
+			},
+		}
+	}
+	orderColumn("order_id", func(o *Order, f *StoreFormat) string { return strconv.FormatInt(o.ID, 10) })
+	orderColumn("date_created", func(o *Order, f *StoreFormat) string { return f.Date(o.DateCreated.Time) })
+	orderColumn("date_modified", func(o *Order, f *StoreFormat) string { return f.Date(o.DateModified.Time) })
+	orderColumn("date_shipped", func(o *Order, f *StoreFormat) string { return f.Date(o.DateShipped.Time) })
+	orderColumn("status", func(o *Order, f *StoreFormat) string { return o.Status })
+	orderColumn("status_id", func(o *Order, f *StoreFormat) string { return strconv.FormatInt(int64(o.StatusID), 10) })
+	orderColumn("customer_id", func(o *Order, f *StoreFormat) string { return strconv.FormatInt(o.CustomerID, 10) })
+	orderColumn("billing_email", func(o *Order, f *StoreFormat) string { return o.BillingAddress.Email })
+	orderColumn("billing_name", func(o *Order, f *StoreFormat) string {
+		return strings.TrimSpace(o.BillingAddress.FirstName + " " + o.BillingAddress.LastName)
+	})
+	orderColumn("billing_country", func(o *Order, f *StoreFormat) string { return o.BillingAddress.CountryIso2 })
+	orderColumn("payment_method", func(o *Order, f *StoreFormat) string { return o.PaymentMethod })
+	orderColumn("channel_id", func(o *Order, f *StoreFormat) string { return strconv.FormatInt(o.ChannelID, 10) })
+	orderColumn("currency_code", func(o *Order, f *StoreFormat) string { return o.CurrencyCode })
+	orderColumn("items_total", func(o *Order, f *StoreFormat) string { return strconv.Itoa(o.ItemsTotal) })
+	orderColumn("subtotal_inc_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.SubtotalIncTax) })
+	orderColumn("subtotal_ex_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.SubtotalExTax) })
+	orderColumn("shipping_cost_inc_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.ShippingCostIncTax) })
+	orderColumn("discount_amount", func(o *Order, f *StoreFormat) string { return f.Money(o.DiscountAmount) })
+	orderColumn("total_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.TotalTax) })
+	orderColumn("total_ex_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.TotalExTax) })
+	orderColumn("total_inc_tax", func(o *Order, f *StoreFormat) string { return f.Money(o.TotalIncTax) })
+	orderColumn("refunded_amount", func(o *Order, f *StoreFormat) string { return f.Money(o.RefundedAmount) })
+	productColumn("product_id", func(p *OrderProduct, f *StoreFormat) string { return strconv.FormatInt(p.ProductID, 10) })
+	productColumn("sku", func(p *OrderProduct, f *StoreFormat) string { return p.Sku })
+	productColumn("product_name", func(p *OrderProduct, f *StoreFormat) string { return p.Name })
+	productColumn("quantity", func(p *OrderProduct, f *StoreFormat) string { return strconv.Itoa(p.Quantity) })
+	productColumn("price_ex_tax", func(p *OrderProduct, f *StoreFormat) string { return f.Money(p.PriceExTax) })
+	productColumn("price_inc_tax", func(p *OrderProduct, f *StoreFormat) string { return f.Money(p.PriceIncTax) })
+	productColumn("line_total_ex_tax", func(p *OrderProduct, f *StoreFormat) string { return f.Money(p.TotalExTax) })
+	productColumn("line_total_inc_tax", func(p *OrderProduct, f *StoreFormat) string { return f.Money(p.TotalIncTax) })
+}
+
+// OrderExporter streams orders and their line items to CSV or JSON Lines
+// Use:
+//
+//	info, _ := client.GetStoreInfo()
+//	e := bigcommerce.NewOrderExporter(client, info)
+//	e.Filters = map[string]string{"min_date_created": "2022-01-01"}
+//	err := e.WriteCSV(os.Stdout)
+type OrderExporter struct {
+	Client *Client
+	Format *StoreFormat
+	Mode   OrderExportMode
+	// Columns are the exported columns in order, defaults to DefaultOrderExportColumns
+	Columns []OrderExportColumn
+	// Filters are query parameters for the orders endpoint, see GetOrders
+	Filters map[string]string
+}
+
+// NewOrderExporter returns an OrderExporter with the default columns, one row per line item
+// and currency and date formatting from the store settings
+func NewOrderExporter(client *Client, info StoreInfo) *OrderExporter {
+	e := &OrderExporter{
+		Client: client,
+		Format: NewStoreFormat(info),
+		Mode:   ExportPerLineItem,
+	}
+	e.SetColumns(DefaultOrderExportColumns...)
+	return e
+}
+
+// SetColumns selects predefined columns by header name, see OrderExportColumns
+func (e *OrderExporter) SetColumns(headers ...string) error {
+	cols := make([]OrderExportColumn, 0, len(headers))
+	for _, h := range headers {
+		col, ok := OrderExportColumns[h]
+		if !ok {
+			return fmt.Errorf("unknown order export column %q", h)
+		}
+		cols = append(cols, col)
+	}
+	e.Columns = cols
+	return nil
+}
+
+// WriteCSV writes the header and all rows as CSV
+func (e *OrderExporter) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	headers := make([]string, len(e.Columns))
+	for i, col := range e.Columns {
+		headers[i] = col.Header
+	}
+	err := cw.Write(headers)
+	if err != nil {
+		return err
+	}
+	err = e.eachRow(func(row []string) error {
+		return cw.Write(row)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONLines writes every row as a JSON object keyed by column header, one per line
+func (e *OrderExporter) WriteJSONLines(w io.Writer) error {
+	return e.eachRow(func(row []string) error {
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, col := range e.Columns {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(col.Header)
+			v, _ := json.Marshal(row[i])
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(v)
+		}
+		buf.WriteString("}\n")
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+func (e *OrderExporter) eachRow(fn func(row []string) error) error {
+	format := e.Format
+	if format == nil {
+		format = &StoreFormat{}
+	}
+	row := func(o *Order, p *OrderProduct) []string {
+		r := make([]string, len(e.Columns))
+		for i, col := range e.Columns {
+			r[i] = col.Value(o, p, format)
+		}
+		return r
+	}
+	return e.Client.IterateOrders(e.Filters, func(o Order) error {
+		if e.Mode == ExportPerOrder {
+			return fn(row(&o, nil))
+		}
+		products, err := e.Client.GetOrderProducts(o.ID)
+		if err != nil && err != ErrNoContent {
+			return err
+		}
+		if len(products) == 0 {
+			// keep orders without line items in the export, with empty product columns
+			return fn(row(&o, nil))
+		}
+		for i := range products {
+			err = fn(row(&o, &products[i]))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// StoreFormat formats money and dates the way the store is configured to
+// The zero value formats plain numbers with two decimals and RFC3339 dates in UTC.
+type StoreFormat struct {
+	CurrencySymbol         string
+	CurrencySymbolLocation string // "left" or "right"
+	DecimalSeparator       string
+	ThousandsSeparator     string
+	DecimalPlaces          int
+	DateLayout             string // Go time layout
+	Location               *time.Location
+}
+
+// NewStoreFormat returns the StoreFormat of the store settings
+func NewStoreFormat(info StoreInfo) *StoreFormat {
+	// the named zone applies DST per date, the offsets only hold for today
+	loc, err := time.LoadLocation(info.Timezone.Name)
+	if err != nil || info.Timezone.Name == "" {
+		offset := info.Timezone.RawOffset
+		if info.Timezone.DstCorrection {
+			offset = info.Timezone.DstOffset
+		}
+		loc = time.FixedZone(info.Timezone.Name, offset)
+	}
+	return &StoreFormat{
+		CurrencySymbol:         info.CurrencySymbol,
+		CurrencySymbolLocation: info.CurrencySymbolLocation,
+		DecimalSeparator:       info.DecimalSeparator,
+		ThousandsSeparator:     info.ThousandsSeparator,
+		DecimalPlaces:          info.DecimalPlaces,
+		DateLayout:             phpDateLayout(info.Timezone.DateFormat.Export),
+		Location:               loc,
+	}
+}
+
+// Money formats a decimal amount string of the API (e.g. "1234.5000")
+// amounts that can't be parsed are returned unchanged
+func (f *StoreFormat) Money(amount string) string {
+	if amount == "" {
+		return ""
+	}
+	places := f.DecimalPlaces
+	if places == 0 && f.DecimalSeparator == "" {
+		places = 2
+	}
+	decimal := f.DecimalSeparator
+	if decimal == "" {
+		decimal = "."
+	}
+	neg, intPart, fracPart, ok := roundDecimal(amount, places)
+	if !ok {
+		return amount
+	}
+	if f.ThousandsSeparator != "" {
+		var b strings.Builder
+		for i, c := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(f.ThousandsSeparator)
+			}
+			b.WriteRune(c)
+		}
+		intPart = b.String()
+	}
+	s := intPart
+	if fracPart != "" {
+		s += decimal + fracPart
+	}
+	if f.CurrencySymbolLocation == "right" {
+		s += f.CurrencySymbol
+	} else {
+		s = f.CurrencySymbol + s
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// roundDecimal rounds a decimal string half away from zero to places decimals without going
+// through float64, returning the sign and the integer and fraction digits
+func roundDecimal(amount string, places int) (neg bool, intPart, fracPart string, ok bool) {
+	s := strings.TrimSpace(amount)
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	intPart, fracPart = s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return false, "", "", false
+	}
+	for _, c := range intPart + fracPart {
+		if c < '0' || c > '9' {
+			return false, "", "", false
+		}
+	}
+	for len(fracPart) <= places {
+		fracPart += "0"
+	}
+	digits := []byte(intPart + fracPart[:places])
+	if fracPart[places] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	n := len(digits) - places
+	intPart = strings.TrimLeft(string(digits[:n]), "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	fracPart = string(digits[n:])
+	if strings.Trim(intPart+fracPart, "0") == "" {
+		neg = false
+	}
+	return neg, intPart, fracPart, true
+}
+
+// Date formats a timestamp in the store's timezone, the zero time is formatted as ""
+func (f *StoreFormat) Date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	layout := f.DateLayout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if f.Location != nil {
+		t = t.In(f.Location)
+	} else {
+		t = t.UTC()
+	}
+	return t.Format(layout)
+}
+
+// phpDateLayouts maps PHP date() format characters, used by the store settings, to Go layouts
+var phpDateLayouts = map[rune]string{
+	'd': "02", 'j': "2", 'D': "Mon", 'l': "Monday",
+	'm': "01", 'n': "1", 'M': "Jan", 'F': "January",
+	'y': "06", 'Y': "2006",
+	'H': "15", 'h': "03", 'g': "3", 'i': "04", 's': "05",
+	'A': "PM", 'a': "pm", 'T': "MST", 'O': "-0700", 'P': "-07:00",
+}
+
+// phpDateLayout converts a PHP date() format to a Go time layout
+// the English ordinal suffix (S) is not supported by Go and is dropped
+func phpDateLayout(format string) string {
+	if format == "" {
+		return ""
+	}
+	var b strings.Builder
+	escaped := false
+	for _, c := range format {
+		if escaped {
+			b.WriteRune(c)
+			escaped = false
+			continue
+		}
+		if c == '\\' {
+			escaped = true
+			continue
+		}
+		if c == 'S' {
+			continue
+		}
+		if l, ok := phpDateLayouts[c]; ok {
+			b.WriteString(l)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
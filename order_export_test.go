@@ -0,0 +1,51 @@
+package bigcommerce
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestStoreFormatMoney(t *testing.T) {
+	f := &StoreFormat{
+		CurrencySymbol:     "$",
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		DecimalPlaces:      2,
+	}
+	tests := []struct {
+		amount, want string
+	}{
+		{"1234.5000", "$1,234.50"},
+		{"-1234567.005", "-$1,234,567.01"},
+		{"0.125", "$0.13"},
+		{"999.995", "$1,000.00"},
+		{"-0.001", "$0.00"},
+		{"12", "$12.00"},
+		{"n/a", "n/a"},
+	}
+	for _, tt := range tests {
+		if got := f.Money(tt.amount); got != tt.want {
+			t.Errorf("Money(%q) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestNewStoreFormatTimezone(t *testing.T) {
+	var info StoreInfo
+	info.Timezone.Name = "Europe/London"
+	info.Timezone.RawOffset = 0
+	info.Timezone.DstOffset = 3600
+	info.Timezone.DstCorrection = true
+	f := NewStoreFormat(info)
+	f.DateLayout = "2006-01-02 15:04"
+
+	winter := time.Date(2021, 1, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2021, 7, 15, 12, 0, 0, 0, time.UTC)
+	if got := f.Date(winter); got != "2021-01-15 12:00" {
+		t.Errorf("winter date %q", got)
+	}
+	if got := f.Date(summer); got != "2021-07-15 13:00" {
+		t.Errorf("summer date %q", got)
+	}
+}
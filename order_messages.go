@@ -0,0 +1,193 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// OrderMessage is a message attached to an order by the customer or the staff
+// The API only reads order messages: Status and IsFlagged can't be changed through it,
+// mark messages read or flagged in the control panel or track that state on your side.
+type OrderMessage struct {
+	ID          int64       `json:"id"`
+	OrderID     int64       `json:"order_id"`
+	StaffID     int64       `json:"staff_id"`
+	CustomerID  int64       `json:"customer_id"`
+	Type        string      `json:"type"`
+	Subject     string      `json:"subject"`
+	Message     string      `json:"message"`
+	Status      string      `json:"status"`
+	IsFlagged   bool        `json:"is_flagged"`
+	DateCreated RFC1123Time `json:"date_created"`
+	Customer    struct {
+		ID        int64  `json:"id"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Email     string `json:"email"`
+		Phone     string `json:"phone"`
+	} `json:"customer"`
+}
+
+// Order message statuses
+const (
+	OrderMessageRead   = "read"
+	OrderMessageUnread = "unread"
+)
+
+// OrderMessageFilter holds the optional filters for listing order messages
+// zero values are not sent. Status and IsFlagged only filter, the API can't update them.
+type OrderMessageFilter struct {
+	Status         string // OrderMessageRead or OrderMessageUnread
+	CustomerID     int64
+	IsFlagged      *bool
+	MinID          int64
+	MaxID          int64
+	MinDateCreated time.Time
+	MaxDateCreated time.Time
+	Page           int
+	Limit          int
+}
+
+func (f OrderMessageFilter) values() url.Values {
+	q := url.Values{}
+	if f.Status != "" {
+		q.Set("status", f.Status)
+	}
+	if f.CustomerID != 0 {
+		q.Set("customer_id", strconv.FormatInt(f.CustomerID, 10))
+	}
+	if f.IsFlagged != nil {
+		q.Set("is_flagged", strconv.FormatBool(*f.IsFlagged))
+	}
+	if f.MinID != 0 {
+		q.Set("min_id", strconv.FormatInt(f.MinID, 10))
+	}
+	if f.MaxID != 0 {
+		q.Set("max_id", strconv.FormatInt(f.MaxID, 10))
+	}
+	if !f.MinDateCreated.IsZero() {
+		q.Set("min_date_created", f.MinDateCreated.Format(time.RFC1123Z))
+	}
+	if !f.MaxDateCreated.IsZero() {
+		q.Set("max_date_created", f.MaxDateCreated.Format(time.RFC1123Z))
+	}
+	if f.Page != 0 {
+		q.Set("page", strconv.Itoa(f.Page))
+	}
+	if f.Limit != 0 {
+		q.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return q
+}
+
+// GetOrderMessages returns the messages of a given order
+func (bc *Client) GetOrderMessages(orderID int64) ([]OrderMessage, error) {
+	return bc.getOrderMessages("/v2/orders/"+strconv.FormatInt(orderID, 10)+"/messages", OrderMessageFilter{})
+}
+
+// GetOrderMessage returns a single message of an order
+// use it with the order ID and Data.Message.OrderMessageID of a store/order/message/created webhook
+func (bc *Client) GetOrderMessage(orderID, messageID int64) (*OrderMessage, error) {
+	messages, err := bc.getOrderMessages("/v2/orders/"+strconv.FormatInt(orderID, 10)+"/messages", OrderMessageFilter{
+		MinID: messageID,
+		MaxID: messageID,
+	})
+	if err != nil {
+		if err == ErrNoContent {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	for i := range messages {
+		if messages[i].ID == messageID {
+			return &messages[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetAllOrderMessages returns the messages of all orders matching orderFilters
+// BigCommerce only lists messages per order, so this reads every matching order and then its
+// messages, one request per order; narrow the scan with orderFilters, e.g. {"min_date_modified": ...}.
+// filter applies to the messages of each order, its CustomerID also narrows the orders scanned.
+// Without order filters, MinDateCreated also narrows the scan to orders modified since then,
+// as adding a message modifies its order.
+func (bc *Client) GetAllOrderMessages(orderFilters map[string]string, filter OrderMessageFilter) ([]OrderMessage, error) {
+	filters := map[string]string{}
+	for k, v := range orderFilters {
+		filters[k] = v
+	}
+	if filter.CustomerID != 0 && filters["customer_id"] == "" {
+		filters["customer_id"] = strconv.FormatInt(filter.CustomerID, 10)
+	}
+	if len(orderFilters) == 0 && !filter.MinDateCreated.IsZero() {
+		filters["min_date_modified"] = filter.MinDateCreated.Format(time.RFC1123Z)
+	}
+	messages := []OrderMessage{}
+	err := bc.IterateOrders(filters, func(o Order) error {
+		msgs, err := bc.getOrderMessages("/v2/orders/"+strconv.FormatInt(o.ID, 10)+"/messages", filter)
+		if err == ErrNoContent {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		messages = append(messages, msgs...)
+		return nil
+	})
+	return messages, err
+}
+
+func (bc *Client) getOrderMessages(path string, filter OrderMessageFilter) ([]OrderMessage, error) {
+	if q := filter.values().Encode(); q != "" {
+		path += "?" + q
+	}
+	req := bc.getAPIRequest(http.MethodGet, path, nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []OrderMessage
+	err = json.Unmarshal(body, &messages)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// SetOrderStaffNotes replaces the staff notes of an order
+// staff notes are only visible in the control panel, never to the customer
+func (bc *Client) SetOrderStaffNotes(orderID int64, notes string) (*Order, error) {
+	url := "/v2/orders/" + strconv.FormatInt(orderID, 10)
+	b, _ := json.Marshal(map[string]string{"staff_notes": notes})
+
+	req := bc.getAPIRequest(http.MethodPut, url, bytes.NewReader(b))
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var order Order
+	err = json.Unmarshal(body, &order)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,37 @@
+package bigcommerce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetAllOrderMessagesNarrowsOrders(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		orderFilters map[string]string
+		want         string
+	}{
+		{nil, since.Format(time.RFC1123Z)},
+		{map[string]string{"status_id": "11"}, ""},
+	}
+	for _, tt := range tests {
+		var got string
+		client := NewClient("store", "token")
+		client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(r.URL.Path, "/v2/orders") {
+				got = r.URL.Query().Get("min_date_modified")
+			}
+			return &http.Response{StatusCode: http.StatusNoContent, Body: ioutil.NopCloser(strings.NewReader("")), Request: r}, nil
+		})}
+		_, err := client.GetAllOrderMessages(tt.orderFilters, OrderMessageFilter{MinDateCreated: since})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("orderFilters %v: min_date_modified = %q, want %q", tt.orderFilters, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// OrderStatus is the numeric status_id of a BigCommerce order
+type OrderStatus int64
+
+// Default BigCommerce order statuses
+const (
+	OrderStatusIncomplete                 OrderStatus = 0
+	OrderStatusPending                    OrderStatus = 1
+	OrderStatusShipped                    OrderStatus = 2
+	OrderStatusPartiallyShipped           OrderStatus = 3
+	OrderStatusRefunded                   OrderStatus = 4
+	OrderStatusCancelled                  OrderStatus = 5
+	OrderStatusDeclined                   OrderStatus = 6
+	OrderStatusAwaitingPayment            OrderStatus = 7
+	OrderStatusAwaitingPickup             OrderStatus = 8
+	OrderStatusAwaitingShipment           OrderStatus = 9
+	OrderStatusCompleted                  OrderStatus = 10
+	OrderStatusAwaitingFulfillment        OrderStatus = 11
+	OrderStatusManualVerificationRequired OrderStatus = 12
+	OrderStatusDisputed                   OrderStatus = 13
+	OrderStatusPartiallyRefunded          OrderStatus = 14
+)
+
+var orderStatusNames = map[OrderStatus]string{
+	OrderStatusIncomplete:                 "Incomplete",
+	OrderStatusPending:                    "Pending",
+	OrderStatusShipped:                    "Shipped",
+	OrderStatusPartiallyShipped:           "Partially Shipped",
+	OrderStatusRefunded:                   "Refunded",
+	OrderStatusCancelled:                  "Cancelled",
+	OrderStatusDeclined:                   "Declined",
+	OrderStatusAwaitingPayment:            "Awaiting Payment",
+	OrderStatusAwaitingPickup:             "Awaiting Pickup",
+	OrderStatusAwaitingShipment:           "Awaiting Shipment",
+	OrderStatusCompleted:                  "Completed",
+	OrderStatusAwaitingFulfillment:        "Awaiting Fulfillment",
+	OrderStatusManualVerificationRequired: "Manual Verification Required",
+	OrderStatusDisputed:                   "Disputed",
+	OrderStatusPartiallyRefunded:          "Partially Refunded",
+}
+
+// String returns the BigCommerce default name of the status
+func (s OrderStatus) String() string {
+	if name, ok := orderStatusNames[s]; ok {
+		return name
+	}
+	return "OrderStatus(" + strconv.FormatInt(int64(s), 10) + ")"
+}
+
+// OrderStatusDetail is an entry of the v2 order_statuses endpoint
+// CustomLabel is the label the merchant set in the control panel, if any
+type OrderStatusDetail struct {
+	ID                OrderStatus `json:"id"`
+	Name              string      `json:"name"`
+	SystemLabel       string      `json:"system_label"`
+	CustomLabel       string      `json:"custom_label"`
+	SystemDescription string      `json:"system_description"`
+}
+
+// Label returns the custom label of the status, or its name if no custom label is set
+func (d OrderStatusDetail) Label() string {
+	if d.CustomLabel != "" {
+		return d.CustomLabel
+	}
+	return d.Name
+}
+
+// GetOrderStatuses returns all order statuses of the store including custom labels
+func (bc *Client) GetOrderStatuses() ([]OrderStatusDetail, error) {
+	req := bc.getAPIRequest(http.MethodGet, "/v2/order_statuses", nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []OrderStatusDetail
+	err = json.Unmarshal(body, &statuses)
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// GetOrderStatusLabels returns the store's label for each order status
+// custom labels set by the merchant take precedence over the default names
+func (bc *Client) GetOrderStatusLabels() (map[OrderStatus]string, error) {
+	statuses, err := bc.GetOrderStatuses()
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[OrderStatus]string, len(statuses))
+	for _, s := range statuses {
+		labels[s.ID] = s.Label()
+	}
+	return labels, nil
+}
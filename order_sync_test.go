@@ -0,0 +1,78 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOrdersClient serves /v2/orders from orders sorted by ID, honouring min_id, limit and page;
+// onPage runs before every page is served
+func fakeOrdersClient(orders *[]Order, onPage func(page int)) *Client {
+	client := NewClient("store", "token")
+	requests := 0
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		if onPage != nil {
+			onPage(requests)
+		}
+		q := r.URL.Query()
+		minID, _ := strconv.ParseInt(q.Get("min_id"), 10, 64)
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		page, _ := strconv.Atoi(q.Get("page"))
+		var matching []Order
+		for _, o := range *orders {
+			if o.ID >= minID {
+				matching = append(matching, o)
+			}
+		}
+		start := (page - 1) * limit
+		if start >= len(matching) {
+			return &http.Response{StatusCode: http.StatusNoContent, Body: ioutil.NopCloser(strings.NewReader("")), Request: r}, nil
+		}
+		end := start + limit
+		if end > len(matching) {
+			end = len(matching)
+		}
+		b, _ := json.Marshal(matching[start:end])
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(string(b))), Request: r}, nil
+	})}
+	return client
+}
+
+func TestOrderSyncerPagesByID(t *testing.T) {
+	modified := RFC1123Time{time.Now().Add(-time.Hour).Truncate(time.Second)}
+	var orders []Order
+	for id := int64(1); id <= 5; id++ {
+		orders = append(orders, Order{ID: id, DateModified: modified})
+	}
+	// order 1 leaves the result set after the first page, which would shift offset pages
+	client := fakeOrdersClient(&orders, func(page int) {
+		if page == 2 {
+			orders = orders[1:]
+		}
+	})
+	syncer := NewOrderSyncer(client)
+	syncer.PageSize = 2
+
+	calls := map[int64]int{}
+	cursor, err := syncer.Sync(OrderSyncCursor{}, func(o Order) error {
+		calls[o.ID]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := int64(1); id <= 5; id++ {
+		if calls[id] != 1 {
+			t.Errorf("order %d passed %d times", id, calls[id])
+		}
+	}
+	if !cursor.ModifiedSince.Equal(modified.Time) || len(cursor.SeenIDs) != 5 {
+		t.Errorf("unexpected cursor %+v", cursor)
+	}
+}
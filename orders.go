@@ -11,10 +11,10 @@ import (
 type Order struct {
 	ID                                      int64        `json:"id"`
 	CustomerID                              int64        `json:"customer_id"`
-	DateCreated                             string       `json:"date_created"`
-	DateModified                            string       `json:"date_modified"`
-	DateShipped                             string       `json:"date_shipped"`
-	StatusID                                int64        `json:"status_id"`
+	DateCreated                             RFC1123Time  `json:"date_created"`
+	DateModified                            RFC1123Time  `json:"date_modified"`
+	DateShipped                             RFC1123Time  `json:"date_shipped"`
+	StatusID                                OrderStatus  `json:"status_id"`
 	Status                                  string       `json:"status"`
 	SubtotalExTax                           string       `json:"subtotal_ex_tax"`
 	SubtotalIncTax                          string       `json:"subtotal_inc_tax"`
@@ -82,19 +82,19 @@ type Order struct {
 }
 
 type OrderAddress struct {
-	FirstName   string        `json:"first_name"`
-	LastName    string        `json:"last_name"`
-	Company     string        `json:"company"`
-	Street1     string        `json:"street_1"`
-	Street2     string        `json:"street_2"`
-	City        string        `json:"city"`
-	State       string        `json:"state"`
-	Zip         string        `json:"zip"`
-	Country     string        `json:"country"`
-	CountryIso2 string        `json:"country_iso2"`
-	Phone       string        `json:"phone"`
-	Email       string        `json:"email"`
-	FormFields  []interface{} `json:"form_fields"`
+	FirstName   string           `json:"first_name"`
+	LastName    string           `json:"last_name"`
+	Company     string           `json:"company"`
+	Street1     string           `json:"street_1"`
+	Street2     string           `json:"street_2"`
+	City        string           `json:"city"`
+	State       string           `json:"state"`
+	Zip         string           `json:"zip"`
+	Country     string           `json:"country"`
+	CountryIso2 string           `json:"country_iso2"`
+	Phone       string           `json:"phone"`
+	Email       string           `json:"email"`
+	FormFields  []OrderFormField `json:"form_fields"`
 }
 
 type OrderProduct struct {
@@ -175,37 +175,37 @@ type ProductOption struct {
 }
 
 type OrderShippingAddress struct {
-	ID                     int64         `json:"id"`
-	OrderID                int64         `json:"order_id"`
-	FirstName              string        `json:"first_name"`
-	LastName               string        `json:"last_name"`
-	Company                string        `json:"company"`
-	Street1                string        `json:"street_1"`
-	Street2                string        `json:"street_2"`
-	City                   string        `json:"city"`
-	Zip                    string        `json:"zip"`
-	Country                string        `json:"country"`
-	CountryIso2            string        `json:"country_iso2"`
-	State                  string        `json:"state"`
-	Email                  string        `json:"email"`
-	Phone                  string        `json:"phone"`
-	ItemsTotal             int           `json:"items_total"`
-	ItemsShipped           int           `json:"items_shipped"`
-	ShippingMethod         string        `json:"shipping_method"`
-	BaseCost               string        `json:"base_cost"`
-	CostExTax              string        `json:"cost_ex_tax"`
-	CostIncTax             string        `json:"cost_inc_tax"`
-	CostTax                string        `json:"cost_tax"`
-	CostTaxClassID         int64         `json:"cost_tax_class_id"`
-	BaseHandlingCost       string        `json:"base_handling_cost"`
-	HandlingCostExTax      string        `json:"handling_cost_ex_tax"`
-	HandlingCostIncTax     string        `json:"handling_cost_inc_tax"`
-	HandlingCostTax        string        `json:"handling_cost_tax"`
-	HandlingCostTaxClassID int64         `json:"handling_cost_tax_class_id"`
-	ShippingZoneID         int64         `json:"shipping_zone_id"`
-	ShippingZoneName       string        `json:"shipping_zone_name"`
-	ShippingQuotes         interface{}   `json:"shipping_quotes"`
-	FormFields             []interface{} `json:"form_fields"`
+	ID                     int64            `json:"id"`
+	OrderID                int64            `json:"order_id"`
+	FirstName              string           `json:"first_name"`
+	LastName               string           `json:"last_name"`
+	Company                string           `json:"company"`
+	Street1                string           `json:"street_1"`
+	Street2                string           `json:"street_2"`
+	City                   string           `json:"city"`
+	Zip                    string           `json:"zip"`
+	Country                string           `json:"country"`
+	CountryIso2            string           `json:"country_iso2"`
+	State                  string           `json:"state"`
+	Email                  string           `json:"email"`
+	Phone                  string           `json:"phone"`
+	ItemsTotal             int              `json:"items_total"`
+	ItemsShipped           int              `json:"items_shipped"`
+	ShippingMethod         string           `json:"shipping_method"`
+	BaseCost               string           `json:"base_cost"`
+	CostExTax              string           `json:"cost_ex_tax"`
+	CostIncTax             string           `json:"cost_inc_tax"`
+	CostTax                string           `json:"cost_tax"`
+	CostTaxClassID         int64            `json:"cost_tax_class_id"`
+	BaseHandlingCost       string           `json:"base_handling_cost"`
+	HandlingCostExTax      string           `json:"handling_cost_ex_tax"`
+	HandlingCostIncTax     string           `json:"handling_cost_inc_tax"`
+	HandlingCostTax        string           `json:"handling_cost_tax"`
+	HandlingCostTaxClassID int64            `json:"handling_cost_tax_class_id"`
+	ShippingZoneID         int64            `json:"shipping_zone_id"`
+	ShippingZoneName       string           `json:"shipping_zone_name"`
+	ShippingQuotes         interface{}      `json:"shipping_quotes"`
+	FormFields             []OrderFormField `json:"form_fields"`
 }
 
 type OrderCoupon struct {
@@ -251,8 +251,32 @@ func (bc *Client) GetOrders(filters map[string]string) ([]Order, error) {
 }
 
 // GetOrder returns a given order
-// filters: request query parameters for BigCommerce orders endpoint, for example {"customer_id": "41"}
+// products, shipping addresses and coupons are resolved when available, see GetOrderWithDetails for typed subresources
 func (bc *Client) GetOrder(orderID int64) (*Order, error) {
+	order, err := bc.getOrder(orderID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := bc.GetOrderProducts(orderID)
+	if err != nil {
+		return order, nil // well, we got the order, but we can't get the products
+	}
+	order.Products = products // this is why we used interface{} for products instead of OrderResource
+	addresses, err := bc.GetOrderShippingAddresses(orderID)
+	if err != nil {
+		return order, nil // well, we got the order, but we can't get the addresses
+	}
+	order.ShippingAddresses = addresses
+	coupons, err := bc.GetOrderCoupons(orderID)
+	if err != nil {
+		return order, nil // well, we got the order, but we can't get the coupons
+	}
+	order.Coupons = coupons
+	return order, nil
+}
+
+// getOrder returns a given order without resolving its subresources
+func (bc *Client) getOrder(orderID int64) (*Order, error) {
 	url := "/v2/orders/" + strconv.FormatInt(orderID, 10)
 
 	req := bc.getAPIRequest(http.MethodGet, url, nil)
@@ -272,27 +296,29 @@ func (bc *Client) GetOrder(orderID int64) (*Order, error) {
 	if err != nil {
 		return nil, err
 	}
-	products, err := bc.GetOrderProducts(orderID)
-	if err != nil {
-		return &order, nil // well, we got the order, but we can't get the products
-	}
-	order.Products = products // this is why we used interface{} for products instead of OrderResource
-	addresses, err := bc.GetOrderShippingAddresses(orderID)
-	if err != nil {
-		return &order, nil // well, we got the order, but we can't get the addresses
-	}
-	order.ShippingAddresses = addresses
-	coupons, err := bc.GetOrderCoupons(orderID)
-	if err != nil {
-		return &order, nil // well, we got the order, but we can't get the coupons
-	}
-	order.Coupons = coupons
 	return &order, nil
 }
 
-// GetOrderProducts returns all products for a given order
+// GetOrderProducts returns all products for a given order, reading every page
 func (bc *Client) GetOrderProducts(orderID int64) ([]OrderProduct, error) {
-	url := "/v2/orders/" + strconv.FormatInt(orderID, 10) + "/products"
+	var products []OrderProduct
+	for page := 1; ; page++ {
+		pageProducts, err := bc.getOrderProductsPage(orderID, page)
+		if err == ErrNoContent && page > 1 {
+			return products, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, pageProducts...)
+		if len(pageProducts) < OrderPageSize {
+			return products, nil
+		}
+	}
+}
+
+func (bc *Client) getOrderProductsPage(orderID int64, page int) ([]OrderProduct, error) {
+	url := "/v2/orders/" + strconv.FormatInt(orderID, 10) + "/products?limit=" + strconv.Itoa(OrderPageSize) + "&page=" + strconv.Itoa(page)
 
 	req := bc.getAPIRequest(http.MethodGet, url, nil)
 	res, err := bc.HTTPClient.Do(req)
@@ -0,0 +1,252 @@
+package bigcommerce
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIScope is an OAuth scope granted to an app or API account
+type APIScope string
+
+// API scopes, the _read_only variants are granted by the full scopes too
+const (
+	APIScopeOrders                           APIScope = "store_v2_orders"
+	APIScopeOrdersReadOnly                   APIScope = "store_v2_orders_read_only"
+	APIScopeProducts                         APIScope = "store_v2_products"
+	APIScopeProductsReadOnly                 APIScope = "store_v2_products_read_only"
+	APIScopeCustomers                        APIScope = "store_v2_customers"
+	APIScopeCustomersReadOnly                APIScope = "store_v2_customers_read_only"
+	APIScopeContent                          APIScope = "store_v2_content"
+	APIScopeContentReadOnly                  APIScope = "store_v2_content_read_only"
+	APIScopeInformation                      APIScope = "store_v2_information"
+	APIScopeInformationReadOnly              APIScope = "store_v2_information_read_only"
+	APIScopeMarketing                        APIScope = "store_v2_marketing"
+	APIScopeMarketingReadOnly                APIScope = "store_v2_marketing_read_only"
+	APIScopeTransactions                     APIScope = "store_v2_transactions"
+	APIScopeTransactionsReadOnly             APIScope = "store_v2_transactions_read_only"
+	APIScopeCarts                            APIScope = "store_cart"
+	APIScopeCartsReadOnly                    APIScope = "store_cart_read_only"
+	APIScopeCheckouts                        APIScope = "store_checkout"
+	APIScopeCheckoutsReadOnly                APIScope = "store_checkout_read_only"
+	APIScopeChannelSettings                  APIScope = "store_channel_settings"
+	APIScopeChannelSettingsReadOnly          APIScope = "store_channel_settings_read_only"
+	APIScopeSites                            APIScope = "store_sites"
+	APIScopeSitesReadOnly                    APIScope = "store_sites_read_only"
+	APIScopeThemes                           APIScope = "store_themes_manage"
+	APIScopeStorefrontAPI                    APIScope = "store_storefront_api"
+	APIScopeStorefrontAPIImpersonation       APIScope = "store_storefront_api_customer_impersonation"
+	APIScopeCustomersLogin                   APIScope = "store_v2_customers_login"
+	APIScopeStoredPaymentInstruments         APIScope = "store_stored_payment_instruments"
+	APIScopeStoredPaymentInstrumentsReadOnly APIScope = "store_stored_payment_instruments_read_only"
+	APIScopeUsersBasicInformation            APIScope = "users_basic_information"
+	APIScopeDefault                          APIScope = "store_v2_default"
+)
+
+// readOnlySuffix marks the read only variant of a scope
+const readOnlySuffix = "_read_only"
+
+// APIScopes is the set of scopes granted to an app or API account
+type APIScopes map[APIScope]bool
+
+// ParseScopes parses the space separated scopes of an AuthContext
+func ParseScopes(scope string) APIScopes {
+	scopes := APIScopes{}
+	for _, s := range strings.Fields(scope) {
+		scopes[APIScope(s)] = true
+	}
+	return scopes
+}
+
+// Scopes returns the scopes granted to the app installation
+func (ac *AuthContext) Scopes() APIScopes {
+	return ParseScopes(ac.Scope)
+}
+
+// RequireScopes returns a *MissingScopesError if any of the scopes wasn't granted to the app installation
+func (ac *AuthContext) RequireScopes(required ...APIScope) error {
+	return ac.Scopes().Require(required...)
+}
+
+// Scopes returns the scopes granted to the store's token
+func (t *StoreToken) Scopes() APIScopes {
+	return ParseScopes(t.Scope)
+}
+
+// Has returns true if the scope is granted, a read only scope is also granted by its full scope
+func (s APIScopes) Has(scope APIScope) bool {
+	if s[scope] {
+		return true
+	}
+	full := strings.TrimSuffix(string(scope), readOnlySuffix)
+	return full != string(scope) && s[APIScope(full)]
+}
+
+// Missing returns the scopes that aren't granted, in the order given
+func (s APIScopes) Missing(required ...APIScope) []APIScope {
+	var missing []APIScope
+	for _, scope := range required {
+		if !s.Has(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// Require returns a *MissingScopesError if any of the scopes isn't granted
+func (s APIScopes) Require(required ...APIScope) error {
+	missing := s.Missing(required...)
+	if len(missing) > 0 {
+		return &MissingScopesError{Missing: missing}
+	}
+	return nil
+}
+
+// List returns the granted scopes sorted by name
+func (s APIScopes) List() []APIScope {
+	list := make([]APIScope, 0, len(s))
+	for scope, ok := range s {
+		if ok {
+			list = append(list, scope)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+	return list
+}
+
+// String returns the scopes in the space separated format of AuthContext.Scope
+func (s APIScopes) String() string {
+	list := s.List()
+	strs := make([]string, len(list))
+	for i, scope := range list {
+		strs[i] = string(scope)
+	}
+	return strings.Join(strs, " ")
+}
+
+// MissingScopesError lists the scopes an operation needs but the app or API account wasn't granted
+type MissingScopesError struct {
+	Missing []APIScope
+}
+
+func (e *MissingScopesError) Error() string {
+	strs := make([]string, len(e.Missing))
+	for i, scope := range e.Missing {
+		strs[i] = string(scope)
+	}
+	return "missing API scopes: " + strings.Join(strs, ", ")
+}
+
+// PreflightCheck is an operation probed by Client.Preflight with a harmless GET request
+type PreflightCheck struct {
+	Name string
+	// Scope is the scope the endpoint needs, reported when the probe is forbidden
+	Scope APIScope
+	// Path is the API path probed, relative to /stores/{hash}
+	Path string
+}
+
+// Preflight checks for the read access of common operations
+// Write access can't be probed without side effects, check it with RequireScopes.
+var (
+	PreflightStoreInfo = PreflightCheck{Name: "store information", Scope: APIScopeInformationReadOnly, Path: "/v2/store"}
+	PreflightOrders    = PreflightCheck{Name: "orders", Scope: APIScopeOrdersReadOnly, Path: "/v2/orders?limit=1"}
+	PreflightProducts  = PreflightCheck{Name: "products", Scope: APIScopeProductsReadOnly, Path: "/v3/catalog/products?limit=1"}
+	PreflightCustomers = PreflightCheck{Name: "customers", Scope: APIScopeCustomersReadOnly, Path: "/v3/customers?limit=1"}
+	PreflightContent   = PreflightCheck{Name: "content", Scope: APIScopeContentReadOnly, Path: "/v3/content/scripts?limit=1"}
+	PreflightChannels  = PreflightCheck{Name: "channels", Scope: APIScopeChannelSettingsReadOnly, Path: "/v3/channels?limit=1"}
+	PreflightMarketing = PreflightCheck{Name: "marketing", Scope: APIScopeMarketingReadOnly, Path: "/v2/coupons?limit=1"}
+)
+
+// DefaultPreflightChecks are the checks run by Preflight when none are given
+var DefaultPreflightChecks = []PreflightCheck{
+	PreflightStoreInfo,
+	PreflightOrders,
+	PreflightProducts,
+	PreflightCustomers,
+	PreflightContent,
+	PreflightChannels,
+}
+
+// PreflightResult is the outcome of a PreflightCheck
+type PreflightResult struct {
+	Check PreflightCheck
+	// Status is the HTTP status of the probe, 0 if the request failed
+	Status int
+	// Err is nil if the operation is allowed
+	Err error
+}
+
+// OK returns true if the operation is allowed
+func (r PreflightResult) OK() bool {
+	return r.Err == nil
+}
+
+// PreflightReport is the result of every check run by Preflight
+type PreflightReport []PreflightResult
+
+// Failed returns the results of the checks that failed
+func (r PreflightReport) Failed() []PreflightResult {
+	var failed []PreflightResult
+	for _, res := range r {
+		if !res.OK() {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Err returns an error describing every failed check, or nil if all passed
+func (r PreflightReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(failed))
+	for i, res := range failed {
+		msgs[i] = res.Check.Name + ": " + res.Err.Error()
+	}
+	return fmt.Errorf("preflight failed: %s", strings.Join(msgs, "; "))
+}
+
+// Preflight probes the endpoints of the checks, DefaultPreflightChecks if none are given,
+// and reports which operations will fail because of an invalid token or missing scopes
+func (bc *Client) Preflight(checks ...PreflightCheck) PreflightReport {
+	if len(checks) == 0 {
+		checks = DefaultPreflightChecks
+	}
+	report := make(PreflightReport, len(checks))
+	for i, check := range checks {
+		report[i] = bc.preflight(check)
+	}
+	return report
+}
+
+func (bc *Client) preflight(check PreflightCheck) PreflightResult {
+	result := PreflightResult{Check: check}
+	req := bc.getAPIRequest(http.MethodGet, check.Path, nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	res.Body.Close()
+	result.Status = res.StatusCode
+	switch {
+	case res.StatusCode < 300:
+	case res.StatusCode == http.StatusUnauthorized:
+		result.Err = fmt.Errorf("invalid or revoked access token (%s)", res.Status)
+	case res.StatusCode == http.StatusForbidden:
+		if check.Scope != "" {
+			result.Err = &MissingScopesError{Missing: []APIScope{check.Scope}}
+		} else {
+			result.Err = fmt.Errorf("access denied (%s)", res.Status)
+		}
+	default:
+		result.Err = fmt.Errorf("unexpected response %s", res.Status)
+	}
+	return result
+}
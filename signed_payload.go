@@ -0,0 +1,56 @@
+package bigcommerce
+
+import (
+	"errors"
+	"time"
+)
+
+// SignedPayloadClaims are the claims of the signed_payload_jwt BigCommerce sends to the
+// /load, /uninstall and /remove_user callbacks
+type SignedPayloadClaims struct {
+	JWTClaims
+	User struct {
+		ID     int64  `json:"id"`
+		Email  string `json:"email"`
+		Locale string `json:"locale"`
+	} `json:"user"`
+	Owner     UserPart `json:"owner"`
+	URL       string   `json:"url"`
+	ChannelID *int64   `json:"channel_id"`
+}
+
+// StoreHash returns the store hash from the sub claim
+func (c *SignedPayloadClaims) StoreHash() string {
+	return StoreHashFromContext(c.Subject)
+}
+
+// ClientRequest returns the claims as the ClientRequest of the legacy signed_payload
+func (c *SignedPayloadClaims) ClientRequest() *ClientRequest {
+	return &ClientRequest{
+		User:      UserPart{ID: c.User.ID, Email: c.User.Email},
+		Owner:     c.Owner,
+		Context:   c.Subject,
+		StoreHash: c.StoreHash(),
+	}
+}
+
+// VerifySignedPayloadJWT verifies a signed_payload_jwt with the app's client secret:
+// HS256 signature, aud (the app's client ID), iss ("bc"), exp, nbf and a "stores/{hash}" sub
+func (bc *App) VerifySignedPayloadJWT(token string) (*SignedPayloadClaims, error) {
+	if bc.AppClientID == "" {
+		return nil, errors.New("no client ID to verify token audience")
+	}
+	var claims SignedPayloadClaims
+	err := verifyHS256(token, bc.AppClientSecret, &claims)
+	if err != nil {
+		return nil, err
+	}
+	err = claims.validate("bc", bc.AppClientID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.StoreHash() == "" || claims.StoreHash() == claims.Subject {
+		return nil, errors.New("invalid token subject, no store context")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,76 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StorefrontClient executes queries against the GraphQL Storefront API of a store
+type StorefrontClient struct {
+	// URL is the GraphQL endpoint of the storefront, e.g. https://store.example.com/graphql
+	URL string
+	// Token is a storefront or customer impersonation token, see Client.CreateStorefrontToken
+	Token string
+	// CustomerID runs queries as the customer, only allowed with a customer impersonation token
+	CustomerID int64
+	HTTPClient HTTPClient
+}
+
+// NewStorefrontClient returns a client for the storefront at storeURL, with or without the /graphql path
+func NewStorefrontClient(storeURL, token string) *StorefrontClient {
+	storeURL = strings.TrimRight(storeURL, "/")
+	if !strings.HasSuffix(storeURL, "/graphql") {
+		storeURL += "/graphql"
+	}
+	return &StorefrontClient{
+		URL:   storeURL,
+		Token: token,
+		HTTPClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+// AsCustomer returns a copy of the client that runs queries as the customer
+// The client must use a customer impersonation token.
+func (sc *StorefrontClient) AsCustomer(customerID int64) *StorefrontClient {
+	c := *sc
+	c.CustomerID = customerID
+	return &c
+}
+
+// Query executes a GraphQL query or mutation and decodes its data into v
+// GraphQL errors are returned as GraphQLErrors, after decoding any partial data.
+func (sc *StorefrontClient) Query(query string, variables map[string]interface{}, v interface{}) error {
+	return sc.Do(GraphQLRequest{Query: query, Variables: variables}, v)
+}
+
+// Do executes a GraphQL request and decodes its data into v
+func (sc *StorefrontClient) Do(gqlReq GraphQLRequest, v interface{}) error {
+	b, err := json.Marshal(gqlReq)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, sc.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", "Bearer "+sc.Token)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("User-Agent", "BigCommerce-Go-SDK")
+	if sc.CustomerID != 0 {
+		req.Header.Add("X-Bc-Customer-Id", strconv.FormatInt(sc.CustomerID, 10))
+	}
+	return doGraphQL(sc.HTTPClient, req, v)
+}
+
+// Paginate calls fn for every edge of the connection at path in the query's data, e.g. "site.products",
+// fetching page after page. The query must declare an $after: String variable and pass it to the connection.
+func (sc *StorefrontClient) Paginate(query string, variables map[string]interface{}, path string, fn func(Edge) error) error {
+	return paginateGraphQL(sc.Do, query, variables, path, fn)
+}
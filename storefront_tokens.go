@@ -0,0 +1,279 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStorefrontTokenTTL is the lifetime of storefront tokens created without an expiry
+const DefaultStorefrontTokenTTL = 24 * time.Hour
+
+// DefaultStorefrontTokenRenewBefore is how long before expiry StorefrontTokens replaces a cached token
+const DefaultStorefrontTokenRenewBefore = time.Hour
+
+// StorefrontTokenOptions are the parameters of a new GraphQL Storefront API token
+type StorefrontTokenOptions struct {
+	// ChannelID defaults to the client's ChannelID
+	ChannelID int64
+	// ExpiresAt defaults to DefaultStorefrontTokenTTL from now
+	ExpiresAt time.Time
+	// AllowedCorsOrigins are the origins allowed to use the token from a browser,
+	// ignored for customer impersonation tokens which must stay server side
+	AllowedCorsOrigins []string
+}
+
+// StorefrontToken is a GraphQL Storefront API token
+type StorefrontToken struct {
+	Token                 string
+	ChannelID             int64
+	ExpiresAt             time.Time
+	AllowedCorsOrigins    []string
+	CustomerImpersonation bool
+}
+
+// Expired returns true if the token expires within d from now
+func (t *StorefrontToken) Expired(d time.Duration) bool {
+	return !time.Now().Add(d).Before(t.ExpiresAt)
+}
+
+type storefrontTokenRequest struct {
+	ChannelID          int64    `json:"channel_id"`
+	ExpiresAt          int64    `json:"expires_at"`
+	AllowedCorsOrigins []string `json:"allowed_cors_origins,omitempty"`
+}
+
+// CreateStorefrontToken creates a token for the GraphQL Storefront API that can be used in the browser
+func (bc *Client) CreateStorefrontToken(opts StorefrontTokenOptions) (*StorefrontToken, error) {
+	return bc.createStorefrontToken("/v3/storefront/api-token", opts, false)
+}
+
+// CreateCustomerImpersonationToken creates a token for the GraphQL Storefront API that can act
+// on behalf of any customer with the X-Bc-Customer-Id header. It must only be used server side.
+func (bc *Client) CreateCustomerImpersonationToken(opts StorefrontTokenOptions) (*StorefrontToken, error) {
+	opts.AllowedCorsOrigins = nil
+	return bc.createStorefrontToken("/v3/storefront/api-token-customer-impersonation", opts, true)
+}
+
+// RevokeStorefrontToken revokes a storefront token before its expiry
+func (bc *Client) RevokeStorefrontToken(token string) error {
+	return bc.revokeStorefrontToken("/v3/storefront/api-token", token)
+}
+
+// RevokeCustomerImpersonationToken revokes a customer impersonation token before its expiry
+func (bc *Client) RevokeCustomerImpersonationToken(token string) error {
+	return bc.revokeStorefrontToken("/v3/storefront/api-token-customer-impersonation", token)
+}
+
+// revoke revokes a storefront or customer impersonation token
+func (t *StorefrontToken) revoke(bc *Client) error {
+	if t.CustomerImpersonation {
+		return bc.RevokeCustomerImpersonationToken(t.Token)
+	}
+	return bc.RevokeStorefrontToken(t.Token)
+}
+
+func (bc *Client) createStorefrontToken(path string, opts StorefrontTokenOptions, impersonation bool) (*StorefrontToken, error) {
+	if opts.ChannelID == 0 {
+		opts.ChannelID = int64(bc.ChannelID)
+	}
+	if opts.ExpiresAt.IsZero() {
+		opts.ExpiresAt = time.Now().Add(DefaultStorefrontTokenTTL)
+	}
+	b, _ := json.Marshal(storefrontTokenRequest{
+		ChannelID:          opts.ChannelID,
+		ExpiresAt:          opts.ExpiresAt.Unix(),
+		AllowedCorsOrigins: opts.AllowedCorsOrigins,
+	})
+	req := bc.getAPIRequest(http.MethodPost, path, bytes.NewReader(b))
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		if body == nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error creating storefront token: %v %s", err, string(body))
+	}
+	var tokenResponse struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(body, &tokenResponse)
+	if err != nil {
+		return nil, err
+	}
+	if tokenResponse.Data.Token == "" {
+		return nil, fmt.Errorf("error creating storefront token: %s", string(body))
+	}
+	return &StorefrontToken{
+		Token:                 tokenResponse.Data.Token,
+		ChannelID:             opts.ChannelID,
+		ExpiresAt:             time.Unix(opts.ExpiresAt.Unix(), 0),
+		AllowedCorsOrigins:    opts.AllowedCorsOrigins,
+		CustomerImpersonation: impersonation,
+	}, nil
+}
+
+func (bc *Client) revokeStorefrontToken(path, token string) error {
+	if token == "" {
+		return errors.New("empty storefront token")
+	}
+	req := bc.getAPIRequest(http.MethodDelete, path, nil)
+	req.Header.Set("Sf-Api-Token", token)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err == ErrNoContent {
+		return nil
+	}
+	if err != nil {
+		if body == nil {
+			return err
+		}
+		return fmt.Errorf("error revoking storefront token: %v %s", err, string(body))
+	}
+	return nil
+}
+
+// StorefrontTokens caches storefront tokens per channel, CORS origins and kind, and replaces them
+// shortly before they expire. The API can't list existing tokens, so Tokens only reports the
+// tokens issued through this cache.
+type StorefrontTokens struct {
+	Client *Client
+	// TTL is the lifetime of new tokens, DefaultStorefrontTokenTTL if zero
+	TTL time.Duration
+	// RenewBefore is how long before expiry a cached token is replaced,
+	// clamped to half of TTL so a new token is served for a while before it's renewed
+	RenewBefore time.Duration
+	// RevokeReplaced revokes a token once it has been replaced. Leave it off when
+	// browsers may still hold the old token.
+	RevokeReplaced bool
+
+	mu     sync.Mutex
+	tokens map[string]*StorefrontToken
+}
+
+// NewStorefrontTokens returns a token cache using the client
+func NewStorefrontTokens(client *Client) *StorefrontTokens {
+	return &StorefrontTokens{
+		Client:      client,
+		TTL:         DefaultStorefrontTokenTTL,
+		RenewBefore: DefaultStorefrontTokenRenewBefore,
+		tokens:      map[string]*StorefrontToken{},
+	}
+}
+
+// Storefront returns a cached storefront token for the channel and CORS origins, creating one
+// if there is none or it is about to expire. channelID 0 means the client's channel.
+func (s *StorefrontTokens) Storefront(channelID int64, allowedCorsOrigins ...string) (*StorefrontToken, error) {
+	return s.get(StorefrontTokenOptions{ChannelID: channelID, AllowedCorsOrigins: allowedCorsOrigins}, false)
+}
+
+// CustomerImpersonation returns a cached customer impersonation token for the channel
+func (s *StorefrontTokens) CustomerImpersonation(channelID int64) (*StorefrontToken, error) {
+	return s.get(StorefrontTokenOptions{ChannelID: channelID}, true)
+}
+
+// Tokens returns the unexpired tokens in the cache
+func (s *StorefrontTokens) Tokens() []StorefrontToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := []StorefrontToken{}
+	for _, t := range s.tokens {
+		if !t.Expired(0) {
+			tokens = append(tokens, *t)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].ExpiresAt.Before(tokens[j].ExpiresAt)
+	})
+	return tokens
+}
+
+// RevokeAll revokes every cached token and empties the cache, returning the first error
+func (s *StorefrontTokens) RevokeAll() error {
+	s.mu.Lock()
+	tokens := s.tokens
+	s.tokens = map[string]*StorefrontToken{}
+	s.mu.Unlock()
+	var firstErr error
+	for _, t := range tokens {
+		if t.Expired(0) {
+			continue
+		}
+		err := t.revoke(s.Client)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *StorefrontTokens) get(opts StorefrontTokenOptions, impersonation bool) (*StorefrontToken, error) {
+	if opts.ChannelID == 0 {
+		opts.ChannelID = int64(s.Client.ChannelID)
+	}
+	origins := append([]string{}, opts.AllowedCorsOrigins...)
+	sort.Strings(origins)
+	key := strconv.FormatBool(impersonation) + "|" + strconv.FormatInt(opts.ChannelID, 10) + "|" + strings.Join(origins, ",")
+
+	// the lock is held while creating so concurrent callers share one new token
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = map[string]*StorefrontToken{}
+	}
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = DefaultStorefrontTokenTTL
+	}
+	renewBefore := s.RenewBefore
+	if renewBefore > ttl/2 {
+		renewBefore = ttl / 2
+	}
+	old := s.tokens[key]
+	if old != nil && !old.Expired(renewBefore) {
+		return old, nil
+	}
+	opts.ExpiresAt = time.Now().Add(ttl)
+	var t *StorefrontToken
+	var err error
+	if impersonation {
+		t, err = s.Client.CreateCustomerImpersonationToken(opts)
+	} else {
+		t, err = s.Client.CreateStorefrontToken(opts)
+	}
+	if err != nil {
+		if old != nil && !old.Expired(0) {
+			// keep serving the old token until it actually expires
+			return old, nil
+		}
+		return nil, err
+	}
+	s.tokens[key] = t
+	if old != nil && s.RevokeReplaced && !old.Expired(0) {
+		go func() {
+			err := old.revoke(s.Client)
+			if err != nil {
+				log.Printf("error revoking replaced storefront token for channel %d: %v", old.ChannelID, err)
+			}
+		}()
+	}
+	return t, nil
+}
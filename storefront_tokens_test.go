@@ -0,0 +1,35 @@
+package bigcommerce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStorefrontTokensClampRenewBefore(t *testing.T) {
+	created := 0
+	client := NewClient("store", "token")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		created++
+		body := `{"data":{"token":"t` + strconv.Itoa(created) + `"}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(body)), Request: r}, nil
+	})}
+	tokens := NewStorefrontTokens(client)
+	tokens.TTL = time.Hour
+	tokens.RenewBefore = 2 * time.Hour
+
+	first, err := tokens.Storefront(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tokens.Storefront(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 1 || first.Token != second.Token {
+		t.Errorf("expected the fresh token to be reused, created %d tokens", created)
+	}
+}
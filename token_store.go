@@ -0,0 +1,211 @@
+package bigcommerce
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StoreToken is the access token of an app installation on a store
+type StoreToken struct {
+	StoreHash   string    `json:"store_hash"`
+	AccessToken string    `json:"access_token"`
+	Scope       string    `json:"scope"`
+	Owner       BCUser    `json:"owner"` // the user who installed the app
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// TokenStore persists the access tokens of app installations by store hash
+// LoadToken returns ErrNotFound for unknown stores.
+type TokenStore interface {
+	SaveToken(token StoreToken) error
+	LoadToken(storeHash string) (*StoreToken, error)
+	DeleteToken(storeHash string) error
+}
+
+// NewStoreToken returns the StoreToken of an AuthContext received on install
+func NewStoreToken(ac *AuthContext) StoreToken {
+	return StoreToken{
+		StoreHash:   StoreHashFromContext(ac.Context),
+		AccessToken: ac.AccessToken,
+		Scope:       ac.Scope,
+		Owner:       ac.User,
+		InstalledAt: time.Now(),
+	}
+}
+
+// StoreHashFromContext returns the store hash of a context like "stores/abc123"
+func StoreHashFromContext(context string) string {
+	return strings.TrimPrefix(context, "stores/")
+}
+
+// MemoryTokenStore is an in-memory TokenStore, tokens are lost on restart
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]StoreToken
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: map[string]StoreToken{},
+	}
+}
+
+// SaveToken implements TokenStore
+func (s *MemoryTokenStore) SaveToken(token StoreToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.StoreHash] = token
+	return nil
+}
+
+// LoadToken implements TokenStore
+func (s *MemoryTokenStore) LoadToken(storeHash string) (*StoreToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[storeHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &token, nil
+}
+
+// DeleteToken implements TokenStore
+func (s *MemoryTokenStore) DeleteToken(storeHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, storeHash)
+	return nil
+}
+
+// FileTokenStore is a TokenStore that keeps all tokens in a single AES-GCM encrypted file
+// It suits apps running as a single instance; use a database backed TokenStore otherwise.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD
+
+	mu     sync.RWMutex
+	tokens map[string]StoreToken
+}
+
+// NewFileTokenStore opens the token file at path, creating it on the first save
+// key is the AES key and must be 16, 24 or 32 bytes long
+func NewFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileTokenStore{
+		path:   path,
+		aead:   aead,
+		tokens: map[string]StoreToken{},
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ns := aead.NonceSize()
+	if len(b) < ns {
+		return nil, errors.New("token file too short")
+	}
+	plain, err := aead.Open(nil, b[:ns], b[ns:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt token file: %v", err)
+	}
+	err = json.Unmarshal(plain, &s.tokens)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveToken implements TokenStore
+func (s *FileTokenStore) SaveToken(token StoreToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.tokens[token.StoreHash]
+	s.tokens[token.StoreHash] = token
+	err := s.write()
+	if err != nil {
+		if existed {
+			s.tokens[token.StoreHash] = old
+		} else {
+			delete(s.tokens, token.StoreHash)
+		}
+	}
+	return err
+}
+
+// LoadToken implements TokenStore
+func (s *FileTokenStore) LoadToken(storeHash string) (*StoreToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[storeHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &token, nil
+}
+
+// DeleteToken implements TokenStore
+func (s *FileTokenStore) DeleteToken(storeHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.tokens[storeHash]
+	if !existed {
+		return nil
+	}
+	delete(s.tokens, storeHash)
+	err := s.write()
+	if err != nil {
+		s.tokens[storeHash] = old
+	}
+	return err
+}
+
+// write encrypts the tokens and replaces the file atomically
+func (s *FileTokenStore) write() error {
+	plain, err := json.Marshal(s.tokens)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	b := s.aead.Seal(nonce, nonce, plain, nil)
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -1,5 +1,10 @@
 package bigcommerce
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // AuthTokenRequest is sent to BigCommerce to get AuthContext
 type AuthTokenRequest struct {
 	ClientID     string `json:"client_id"`
@@ -91,3 +96,44 @@ type Pagination struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"total_pages"`
 }
+
+// RFC1123Time is a time.Time that is encoded as an RFC1123Z string, as used by the v2 API
+// (e.g. "Tue, 20 Nov 2012 00:00:00 +0000"). An empty string decodes to the zero time
+// and the zero time encodes to an empty string.
+type RFC1123Time struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler
+func (t RFC1123Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(t.Format(time.RFC1123Z))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *RFC1123Time) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		if string(b) == "null" {
+			t.Time = time.Time{}
+			return nil
+		}
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC1123Z, s)
+	if err != nil {
+		parsed, err = time.Parse(time.RFC1123, s)
+		if err != nil {
+			return err
+		}
+	}
+	t.Time = parsed
+	return nil
+}
@@ -6,9 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"reflect"
+	"net/url"
 	"strconv"
-	"strings"
 )
 
 type WebhookPayload struct {
@@ -73,19 +72,83 @@ func GetWebhookPayload(r *http.Request) (*WebhookPayload, []byte, error) {
 	return &payload, bytes, nil
 }
 
+// WebhookFilter holds the optional filters for listing webhooks, zero values are not sent
+type WebhookFilter struct {
+	IsActive    *bool
+	Scope       string
+	Destination string
+}
+
+// WebhookUpdate is the payload to update a webhook, nil fields are left unchanged
+type WebhookUpdate struct {
+	Scope       *string `json:"scope,omitempty"`
+	Destination *string `json:"destination,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+	// Headers replaces the webhook's headers when not nil, an empty map removes them
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, sending Headers whenever it's not nil
+func (u WebhookUpdate) MarshalJSON() ([]byte, error) {
+	type update WebhookUpdate
+	v := struct {
+		update
+		Headers *map[string]string `json:"headers,omitempty"`
+	}{update: update(u)}
+	if u.Headers != nil {
+		v.Headers = &u.Headers
+	}
+	return json.Marshal(v)
+}
+
+// GetWebhooks returns all webhooks of the app
 func (bc *Client) GetWebhooks() ([]Webhook, error) {
-	url := "/v3/hooks?limit=250"
+	return bc.GetAllWebhooks(WebhookFilter{})
+}
+
+// GetAllWebhooks returns all webhooks of the app matching the filter, reading every page
+func (bc *Client) GetAllWebhooks(filter WebhookFilter) ([]Webhook, error) {
+	ws := []Webhook{}
+	for page := 1; ; page++ {
+		wsp, more, err := bc.GetWebhooksPage(filter, page)
+		if err != nil {
+			if err == ErrNoContent {
+				return ws, nil
+			}
+			return ws, err
+		}
+		ws = append(ws, wsp...)
+		if !more {
+			return ws, nil
+		}
+	}
+}
 
-	req := bc.getAPIRequest(http.MethodGet, url, nil)
+// GetWebhooksPage returns a page of webhooks matching the filter and whether there are more pages
+func (bc *Client) GetWebhooksPage(filter WebhookFilter, page int) ([]Webhook, bool, error) {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("limit", "250")
+	if filter.IsActive != nil {
+		q.Set("is_active", strconv.FormatBool(*filter.IsActive))
+	}
+	if filter.Scope != "" {
+		q.Set("scope", filter.Scope)
+	}
+	if filter.Destination != "" {
+		q.Set("destination", filter.Destination)
+	}
+
+	req := bc.getAPIRequest(http.MethodGet, "/v3/hooks?"+q.Encode(), nil)
 	res, err := bc.HTTPClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	defer res.Body.Close()
 	body, err := processBody(res)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	var webhooksResponse struct {
@@ -95,67 +158,237 @@ func (bc *Client) GetWebhooks() ([]Webhook, error) {
 		} `json:"meta"`
 	}
 	err = json.Unmarshal(body, &webhooksResponse)
+	if err != nil {
+		return nil, false, err
+	}
+	p := webhooksResponse.Meta.Pagination
+	return webhooksResponse.Data, p.CurrentPage < p.TotalPages, nil
+}
+
+// GetWebhook returns a webhook by ID
+func (bc *Client) GetWebhook(webhookID int64) (*Webhook, error) {
+	req := bc.getAPIRequest(http.MethodGet, "/v3/hooks/"+strconv.FormatInt(webhookID, 10), nil)
+	return bc.doWebhookRequest(req)
+}
+
+// UpdateWebhook updates the given fields of a webhook
+func (bc *Client) UpdateWebhook(webhookID int64, update WebhookUpdate) (*Webhook, error) {
+	b, _ := json.Marshal(update)
+	req := bc.getAPIRequest(http.MethodPut, "/v3/hooks/"+strconv.FormatInt(webhookID, 10), bytes.NewReader(b))
+	return bc.doWebhookRequest(req)
+}
+
+// ActivateWebhook turns delivery of a webhook on
+func (bc *Client) ActivateWebhook(webhookID int64) (*Webhook, error) {
+	active := true
+	return bc.UpdateWebhook(webhookID, WebhookUpdate{IsActive: &active})
+}
+
+// DeactivateWebhook turns delivery of a webhook off without deleting it
+func (bc *Client) DeactivateWebhook(webhookID int64) (*Webhook, error) {
+	active := false
+	return bc.UpdateWebhook(webhookID, WebhookUpdate{IsActive: &active})
+}
+
+// DeleteWebhook deletes a webhook
+func (bc *Client) DeleteWebhook(webhookID int64) error {
+	req := bc.getAPIRequest(http.MethodDelete, "/v3/hooks/"+strconv.FormatInt(webhookID, 10), nil)
+	_, err := bc.doWebhookRequest(req)
+	return err
+}
+
+func (bc *Client) doWebhookRequest(req *http.Request) (*Webhook, error) {
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		if body == nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error processing response body: %v %s", err, string(body))
+	}
+
+	var webhookResponse struct {
+		Data Webhook `json:"data"`
+	}
+	err = json.Unmarshal(body, &webhookResponse)
 	if err != nil {
 		return nil, err
 	}
-	return webhooksResponse.Data, nil
+	return &webhookResponse.Data, nil
 }
 
-// CreateWebhook creates a new webhook or activates it if it already exists but inactive
+// CreateWebhook creates a new webhook, or activates and updates the headers of an existing
+// webhook with the same scope and destination
 func (bc *Client) CreateWebhook(scope, destination string, headers map[string]string) (int64, error) {
-	url := "/v3/hooks"
-
-	webhooks, err := bc.GetWebhooks()
+	webhooks, err := bc.GetAllWebhooks(WebhookFilter{Scope: scope})
 	if err != nil {
 		return 0, err
 	}
 	for _, webhook := range webhooks {
-		if webhook.Scope == scope && webhook.Destination == destination && reflect.DeepEqual(headers, headers) {
-			if webhook.IsActive {
-				return webhook.ID, nil
+		if webhook.Scope != scope || webhook.Destination != destination {
+			continue
+		}
+		if webhook.IsActive && headersEqual(webhook.Headers, headers) {
+			return webhook.ID, nil
+		}
+		active := true
+		_, err = bc.UpdateWebhook(webhook.ID, WebhookUpdate{IsActive: &active, Headers: replaceHeaders(headers)})
+		if err != nil {
+			return 0, err
+		}
+		return webhook.ID, nil
+	}
+
+	created, err := bc.createWebhook(WebhookSpec{
+		Scope:       scope,
+		Destination: destination,
+		Headers:     headers,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// headersEqual compares webhook headers, treating nil and empty maps as equal
+func headersEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// replaceHeaders returns the headers for a WebhookUpdate that replaces all existing headers,
+// a nil map becomes an empty one so that the existing headers are removed
+func replaceHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return map[string]string{}
+	}
+	return headers
+}
+
+// WebhookSpec is a desired webhook for EnsureWebhooks
+type WebhookSpec struct {
+	Scope       string
+	Destination string
+	Headers     map[string]string
+}
+
+// WebhookDiff reports the changes made by EnsureWebhooks
+type WebhookDiff struct {
+	Created   []Webhook
+	Updated   []Webhook
+	Deleted   []Webhook
+	Unchanged []Webhook
+}
+
+// Changed returns true if EnsureWebhooks changed any webhook
+func (d *WebhookDiff) Changed() bool {
+	return len(d.Created)+len(d.Updated)+len(d.Deleted) > 0
+}
+
+// EnsureWebhooks reconciles the app's webhooks with the desired list:
+// hooks with the same scope and destination are activated and get their headers updated,
+// hooks of a desired scope with another destination are moved to the desired destination,
+// missing hooks are created and all other hooks of the app are deleted.
+// The diff contains the changes made until the first error.
+func (bc *Client) EnsureWebhooks(desired []WebhookSpec) (*WebhookDiff, error) {
+	diff := &WebhookDiff{}
+	existing, err := bc.GetWebhooks()
+	if err != nil {
+		return diff, err
+	}
+	used := make([]bool, len(existing))
+	matched := make([]int, len(desired))
+	// exact matches on scope and destination first, then reuse hooks of the same scope
+	for i, spec := range desired {
+		matched[i] = -1
+		for j, w := range existing {
+			if !used[j] && w.Scope == spec.Scope && w.Destination == spec.Destination {
+				matched[i], used[j] = j, true
+				break
 			}
-			req := bc.getAPIRequest(http.MethodPut, url+"/"+strconv.FormatInt(webhook.ID, 10), strings.NewReader(`{"is_active": true}`))
-			res, err := bc.HTTPClient.Do(req)
-			if err != nil {
-				return 0, err
+		}
+	}
+	for i, spec := range desired {
+		if matched[i] >= 0 {
+			continue
+		}
+		for j, w := range existing {
+			if !used[j] && w.Scope == spec.Scope {
+				matched[i], used[j] = j, true
+				break
 			}
-			defer res.Body.Close()
-			body, err := processBody(res)
+		}
+	}
+
+	for i, spec := range desired {
+		if matched[i] < 0 {
+			created, err := bc.createWebhook(spec)
 			if err != nil {
-				return 0, fmt.Errorf("error processing response body: %v %s", err, string(body))
+				return diff, err
 			}
-			return webhook.ID, nil
+			diff.Created = append(diff.Created, *created)
+			continue
+		}
+		w := existing[matched[i]]
+		if w.IsActive && w.Destination == spec.Destination && headersEqual(w.Headers, spec.Headers) {
+			diff.Unchanged = append(diff.Unchanged, w)
+			continue
+		}
+		active := true
+		destination := spec.Destination
+		updated, err := bc.UpdateWebhook(w.ID, WebhookUpdate{
+			Destination: &destination,
+			IsActive:    &active,
+			Headers:     replaceHeaders(spec.Headers),
+		})
+		if err != nil {
+			return diff, err
 		}
+		diff.Updated = append(diff.Updated, *updated)
 	}
 
+	for j, w := range existing {
+		if used[j] {
+			continue
+		}
+		err = bc.DeleteWebhook(w.ID)
+		if err != nil {
+			return diff, err
+		}
+		diff.Deleted = append(diff.Deleted, w)
+	}
+	return diff, nil
+}
+
+func (bc *Client) createWebhook(spec WebhookSpec) (*Webhook, error) {
 	payload := struct {
 		Scope       string            `json:"scope"`
 		Destination string            `json:"destination"`
+		IsActive    bool              `json:"is_active"`
 		Headers     map[string]string `json:"headers,omitempty"`
 	}{
-		Scope:       scope,
-		Destination: destination,
-	}
-	if headers != nil {
-		payload.Headers = headers
+		Scope:       spec.Scope,
+		Destination: spec.Destination,
+		IsActive:    true,
+		Headers:     spec.Headers,
 	}
 	reqJSON, _ := json.Marshal(payload)
-
-	req := bc.getAPIRequest(http.MethodPost, url, bytes.NewReader(reqJSON))
-	res, err := bc.HTTPClient.Do(req)
+	req := bc.getAPIRequest(http.MethodPost, "/v3/hooks", bytes.NewReader(reqJSON))
+	webhook, err := bc.doWebhookRequest(req)
 	if err != nil {
-		return 0, err
-	}
-
-	defer res.Body.Close()
-	body, err := processBody(res)
-	if err != nil {
-		return 0, fmt.Errorf("error processing response body: %v %s (%s)", err, string(body), string(reqJSON))
-	}
-	var respWebhook Webhook
-	err = json.Unmarshal(body, &respWebhook)
-	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("%v (%s)", err, string(reqJSON))
 	}
-	return respWebhook.ID, nil
+	return webhook, nil
 }
@@ -0,0 +1,161 @@
+package bigcommerce
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookDedupTTL covers the BigCommerce retry schedule of a failed delivery
+const DefaultWebhookDedupTTL = 48 * time.Hour
+
+// DefaultWebhookDedupLease is how long an event stays in progress if its handler never finishes,
+// e.g. because the process crashed, before a retry may claim it again
+const DefaultWebhookDedupLease = 5 * time.Minute
+
+// ErrWebhookInProgress is returned for a retry of an event whose first delivery is still being handled
+var ErrWebhookInProgress = errors.New("webhook event is being processed")
+
+// DedupState is the state of a webhook event in a DedupStore
+type DedupState int
+
+const (
+	// DedupClaimed means the event was new and is now in progress for the caller
+	DedupClaimed DedupState = iota
+	// DedupInProgress means another delivery of the event is being handled
+	DedupInProgress
+	// DedupDone means the event was handled successfully
+	DedupDone
+)
+
+// DedupStore remembers which webhook events are being or were processed
+// Implementations must be safe for concurrent use, e.g. backed by Redis
+// (SET key "in_progress" NX PX lease, then SET key "done" PX ttl) or a table with a unique index.
+type DedupStore interface {
+	// Claim marks key in progress for lease if it's unknown or its lease expired and returns
+	// DedupClaimed, otherwise it returns the current state of key
+	Claim(ctx context.Context, key string, lease time.Duration) (DedupState, error)
+	// Complete marks key done for ttl
+	Complete(ctx context.Context, key string, ttl time.Duration) error
+	// Release forgets key, so a retried delivery is processed again
+	Release(ctx context.Context, key string) error
+}
+
+// WebhookDedupKey returns the key identifying a webhook event across retries
+func WebhookDedupKey(e *WebhookEvent) string {
+	return e.Producer + "|" + e.Scope + "|" + e.Hash + "|" + strconv.FormatInt(e.CreatedAt.Unix(), 10)
+}
+
+// DedupWebhooks returns a WebhookMiddleware that runs the handlers only once per event
+// Duplicates of a handled event are acknowledged without running the handlers. A duplicate
+// arriving while the first delivery is still handled fails with 503 and ErrWebhookInProgress,
+// so BigCommerce retries it and learns the outcome of the first delivery. When a handler
+// fails, the event is released so the retry is processed again. ttl defaults to DefaultWebhookDedupTTL.
+func DedupWebhooks(store DedupStore, ttl time.Duration) WebhookMiddleware {
+	if ttl <= 0 {
+		ttl = DefaultWebhookDedupTTL
+	}
+	return func(next WebhookHandler) WebhookHandler {
+		return WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) (err error) {
+			if e.Hash == "" {
+				return next.HandleWebhook(ctx, e)
+			}
+			key := WebhookDedupKey(e)
+			state, err := store.Claim(ctx, key, DefaultWebhookDedupLease)
+			if err != nil {
+				return err
+			}
+			switch state {
+			case DedupDone:
+				return nil
+			case DedupInProgress:
+				return &WebhookError{Status: http.StatusServiceUnavailable, Err: ErrWebhookInProgress}
+			}
+			defer func() {
+				rec := recover()
+				if err == nil && rec == nil {
+					if cerr := store.Complete(ctx, key, ttl); cerr != nil {
+						log.Printf("webhook dedup complete %s: %v", key, cerr)
+					}
+					return
+				}
+				if rerr := store.Release(ctx, key); rerr != nil {
+					log.Printf("webhook dedup release %s: %v", key, rerr)
+				}
+				if rec != nil {
+					panic(rec)
+				}
+			}()
+			return next.HandleWebhook(ctx, e)
+		})
+	}
+}
+
+// MemoryDedupStore is an in-memory DedupStore with expiring keys
+// It only de-duplicates within one process, use a shared store when running several instances.
+type MemoryDedupStore struct {
+	mu        sync.Mutex
+	keys      map[string]dedupEntry
+	lastSweep time.Time
+}
+
+type dedupEntry struct {
+	done    bool
+	expires time.Time
+}
+
+// NewMemoryDedupStore returns an empty MemoryDedupStore
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{
+		keys: map[string]dedupEntry{},
+	}
+}
+
+// Claim implements DedupStore
+func (s *MemoryDedupStore) Claim(ctx context.Context, key string, lease time.Duration) (DedupState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+	if e, ok := s.keys[key]; ok && now.Before(e.expires) {
+		if e.done {
+			return DedupDone, nil
+		}
+		return DedupInProgress, nil
+	}
+	s.keys[key] = dedupEntry{expires: now.Add(lease)}
+	return DedupClaimed, nil
+}
+
+// Complete implements DedupStore
+func (s *MemoryDedupStore) Complete(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key] = dedupEntry{done: true, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release implements DedupStore
+func (s *MemoryDedupStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+// sweep drops expired keys, at most once a minute
+func (s *MemoryDedupStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for k, e := range s.keys {
+		if !now.Before(e.expires) {
+			delete(s.keys, k)
+		}
+	}
+}
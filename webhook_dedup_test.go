@@ -0,0 +1,54 @@
+package bigcommerce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDedupWebhooks(t *testing.T) {
+	store := NewMemoryDedupStore()
+	started := make(chan struct{})
+	finish := make(chan error)
+	calls := 0
+	h := DedupWebhooks(store, time.Minute)(WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) error {
+		calls++
+		started <- struct{}{}
+		return <-finish
+	}))
+	e := &WebhookEvent{WebhookMeta: WebhookMeta{Producer: "stores/abc", Scope: ScopeOrderCreated, Hash: "h", CreatedAt: time.Now()}}
+
+	first := make(chan error)
+	go func() { first <- h.HandleWebhook(context.Background(), e) }()
+	<-started
+
+	// a retry of an in-progress event is rejected so BigCommerce delivers it again
+	err := h.HandleWebhook(context.Background(), e)
+	var werr *WebhookError
+	if !errors.As(err, &werr) || werr.Status != http.StatusServiceUnavailable || !errors.Is(err, ErrWebhookInProgress) {
+		t.Fatalf("expected 503 for an in-progress event, got %v", err)
+	}
+
+	// a failed first attempt releases the event for the retry
+	finish <- errors.New("failed")
+	if err := <-first; err == nil {
+		t.Fatal("expected the handler error")
+	}
+	go func() { first <- h.HandleWebhook(context.Background(), e) }()
+	<-started
+	finish <- nil
+	if err := <-first; err != nil {
+		t.Fatal(err)
+	}
+
+	// a finished event is acknowledged without running the handler
+	err = h.HandleWebhook(context.Background(), e)
+	if err != nil {
+		t.Fatalf("expected a duplicate of a finished event to be acknowledged, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 handler calls, got %d", calls)
+	}
+}
@@ -0,0 +1,200 @@
+package bigcommerce
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEnrichCacheTTL is how long WebhookEnricher reuses a fetched resource
+const DefaultEnrichCacheTTL = 5 * time.Second
+
+// WebhookEnricher fetches the resource referenced by a webhook event and attaches it to
+// WebhookEvent.Resource, where the typed events pick it up (e.g. OrderEvent.Order).
+// Concurrent events referencing the same resource share a single request and the result is
+// reused for CacheTTL, so bursts of events for one order only fetch it once. A request is only
+// shared with events created before it started, so a handler never sees the resource as it was
+// before the change it was notified about.
+// Supported scopes: orders, products, SKUs and inventory (product with variants),
+// customers and customer addresses, carts; deleted resources are not fetched.
+// Use:
+//
+//	enricher := bigcommerce.NewWebhookEnricher(func(storeHash string) (*bigcommerce.Client, error) {
+//		return app.NewClient(storeHash, tokenFor(storeHash)), nil
+//	})
+//	router.Use(verifier.Middleware(), enricher.Middleware())
+type WebhookEnricher struct {
+	// Clients returns the API client of a store
+	Clients func(storeHash string) (*Client, error)
+	// CacheTTL defaults to DefaultEnrichCacheTTL, a negative value disables caching
+	CacheTTL time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]*enrichCall
+	cache    map[string]*enrichCall
+}
+
+// errEnrichPanic is the result shared with waiting events when fetching a resource panicked
+var errEnrichPanic = errors.New("webhook enricher: fetching the resource panicked")
+
+type enrichCall struct {
+	wg       sync.WaitGroup
+	started  time.Time
+	expires  time.Time
+	resource interface{}
+	err      error
+}
+
+// NewWebhookEnricher returns a WebhookEnricher using clients to get the client of a store
+func NewWebhookEnricher(clients func(storeHash string) (*Client, error)) *WebhookEnricher {
+	return &WebhookEnricher{
+		Clients:  clients,
+		CacheTTL: DefaultEnrichCacheTTL,
+	}
+}
+
+// Middleware returns a WebhookMiddleware that enriches events before the handlers run
+// A failed fetch fails the event, so BigCommerce retries the delivery.
+func (en *WebhookEnricher) Middleware() WebhookMiddleware {
+	return func(next WebhookHandler) WebhookHandler {
+		return WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) error {
+			err := en.Enrich(ctx, e)
+			if err != nil {
+				return err
+			}
+			return next.HandleWebhook(ctx, e)
+		})
+	}
+}
+
+// Enrich sets e.Resource to the resource referenced by the event
+// Events of unsupported scopes and resources not found anymore are left unchanged.
+func (en *WebhookEnricher) Enrich(ctx context.Context, e *WebhookEvent) error {
+	if e.Resource != nil || strings.HasSuffix(e.Scope, "/deleted") {
+		return nil
+	}
+	d, err := e.data()
+	if err != nil {
+		return err
+	}
+	var kind, id string
+	switch {
+	case e.Scope == ScopeCartConverted:
+		kind, id = "order", string(d.OrderID)
+	case strings.HasPrefix(e.Scope, "store/order/"):
+		kind, id = "order", string(d.ID)
+	case strings.HasPrefix(e.Scope, "store/product/"):
+		kind, id = "product", string(d.ID)
+	case strings.HasPrefix(e.Scope, "store/sku/"):
+		kind, id = "product", strconv.FormatInt(d.skuProductID(), 10)
+	case strings.HasPrefix(e.Scope, "store/customer/address/"):
+		kind, id = "customer", strconv.FormatInt(d.Address.CustomerID, 10)
+	case strings.HasPrefix(e.Scope, "store/customer/"):
+		kind, id = "customer", string(d.ID)
+	case e.Scope == ScopeCartAbandoned:
+		// abandoned carts can't be read with the management API anymore
+		return nil
+	case strings.HasPrefix(e.Scope, "store/cart/"):
+		kind, id = "cart", string(d.CartID)
+		if id == "" {
+			id = string(d.ID)
+		}
+	default:
+		return nil
+	}
+	if id == "" || id == "0" {
+		return nil
+	}
+	resource, err := en.fetch(e.StoreHash(), kind, id, e.CreatedAt)
+	if err == ErrNotFound {
+		// gone by now, retrying the delivery won't help
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	e.Resource = resource
+	return nil
+}
+
+// fetch returns the resource, sharing in-flight requests and cached results that started after
+// the event was created, so the resource never predates the change the event notifies about
+func (en *WebhookEnricher) fetch(storeHash, kind, id string, createdAt time.Time) (interface{}, error) {
+	key := storeHash + "|" + kind + "|" + id
+	// created_at has a resolution of one second
+	fresh := func(c *enrichCall) bool {
+		return !createdAt.IsZero() && !c.started.Before(createdAt.Add(time.Second))
+	}
+	en.mu.Lock()
+	if en.inflight == nil {
+		en.inflight = map[string]*enrichCall{}
+		en.cache = map[string]*enrichCall{}
+	}
+	now := time.Now()
+	if c, ok := en.cache[key]; ok {
+		if !now.Before(c.expires) {
+			delete(en.cache, key)
+		} else if fresh(c) {
+			en.mu.Unlock()
+			return c.resource, c.err
+		}
+	}
+	if c, ok := en.inflight[key]; ok && fresh(c) {
+		en.mu.Unlock()
+		c.wg.Wait()
+		return c.resource, c.err
+	}
+	c := &enrichCall{started: now, err: errEnrichPanic}
+	c.wg.Add(1)
+	en.inflight[key] = c
+	en.mu.Unlock()
+
+	// runs even if load panics, so waiters are released and the key doesn't stay in flight
+	defer func() {
+		en.mu.Lock()
+		if en.inflight[key] == c {
+			delete(en.inflight, key)
+		}
+		ttl := en.CacheTTL
+		if ttl == 0 {
+			ttl = DefaultEnrichCacheTTL
+		}
+		if ttl > 0 && c.err == nil {
+			for k, r := range en.cache {
+				if !now.Before(r.expires) {
+					delete(en.cache, k)
+				}
+			}
+			c.expires = time.Now().Add(ttl)
+			en.cache[key] = c
+		}
+		en.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.resource, c.err = en.load(storeHash, kind, id)
+	return c.resource, c.err
+}
+
+func (en *WebhookEnricher) load(storeHash, kind, id string) (interface{}, error) {
+	client, err := en.Clients(storeHash)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "order":
+		n, _ := strconv.ParseInt(id, 10, 64)
+		return client.GetOrder(n)
+	case "product":
+		n, _ := strconv.ParseInt(id, 10, 64)
+		return client.GetProductByID(n)
+	case "customer":
+		n, _ := strconv.ParseInt(id, 10, 64)
+		return client.GetCustomerByID(n)
+	case "cart":
+		return client.GetCart(id)
+	}
+	return nil, nil
+}
@@ -0,0 +1,43 @@
+package bigcommerce
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookEnricherFetch(t *testing.T) {
+	var calls int32
+	en := NewWebhookEnricher(func(storeHash string) (*Client, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("boom")
+		}
+		return nil, ErrNotFound
+	})
+
+	func() {
+		defer func() { recover() }()
+		en.fetch("store", "order", "1", time.Now().Add(-time.Minute))
+	}()
+	done := make(chan struct{})
+	go func() {
+		en.fetch("store", "order", "1", time.Now().Add(-time.Minute))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fetch blocked after a panic")
+	}
+
+	// a cached result is only reused for events created before the fetch started
+	en.cache["store|order|2"] = &enrichCall{started: time.Now(), expires: time.Now().Add(time.Minute), resource: "cached"}
+	r, _ := en.fetch("store", "order", "2", time.Now().Add(-time.Minute))
+	if r != "cached" {
+		t.Errorf("expected cached resource for an older event, got %v", r)
+	}
+	r, _ = en.fetch("store", "order", "2", time.Now())
+	if r == "cached" {
+		t.Error("cached resource reused for an event created after the fetch")
+	}
+}
@@ -0,0 +1,484 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook scopes
+const (
+	ScopeOrderCreated                 = "store/order/created"
+	ScopeOrderUpdated                 = "store/order/updated"
+	ScopeOrderArchived                = "store/order/archived"
+	ScopeOrderStatusUpdated           = "store/order/statusUpdated"
+	ScopeOrderMessageCreated          = "store/order/message/created"
+	ScopeOrderRefundCreated           = "store/order/refund/created"
+	ScopeProductCreated               = "store/product/created"
+	ScopeProductUpdated               = "store/product/updated"
+	ScopeProductDeleted               = "store/product/deleted"
+	ScopeProductInventoryUpdated      = "store/product/inventory/updated"
+	ScopeProductInventoryOrderUpdated = "store/product/inventory/order/updated"
+	ScopeCategoryCreated              = "store/category/created"
+	ScopeCategoryUpdated              = "store/category/updated"
+	ScopeCategoryDeleted              = "store/category/deleted"
+	ScopeSKUCreated                   = "store/sku/created"
+	ScopeSKUUpdated                   = "store/sku/updated"
+	ScopeSKUDeleted                   = "store/sku/deleted"
+	ScopeSKUInventoryUpdated          = "store/sku/inventory/updated"
+	ScopeSKUInventoryOrderUpdated     = "store/sku/inventory/order/updated"
+	ScopeCustomerCreated              = "store/customer/created"
+	ScopeCustomerUpdated              = "store/customer/updated"
+	ScopeCustomerDeleted              = "store/customer/deleted"
+	ScopeCustomerAddressCreated       = "store/customer/address/created"
+	ScopeCustomerAddressUpdated       = "store/customer/address/updated"
+	ScopeCustomerAddressDeleted       = "store/customer/address/deleted"
+	ScopeCartCreated                  = "store/cart/created"
+	ScopeCartUpdated                  = "store/cart/updated"
+	ScopeCartDeleted                  = "store/cart/deleted"
+	ScopeCartAbandoned                = "store/cart/abandoned"
+	ScopeCartCouponApplied            = "store/cart/couponApplied"
+	ScopeCartConverted                = "store/cart/converted"
+	ScopeCartLineItemCreated          = "store/cart/lineItem/created"
+	ScopeCartLineItemUpdated          = "store/cart/lineItem/updated"
+	ScopeCartLineItemDeleted          = "store/cart/lineItem/deleted"
+	ScopeShipmentCreated              = "store/shipment/created"
+	ScopeShipmentUpdated              = "store/shipment/updated"
+	ScopeShipmentDeleted              = "store/shipment/deleted"
+	ScopeSubscriberCreated            = "store/subscriber/created"
+	ScopeSubscriberUpdated            = "store/subscriber/updated"
+	ScopeSubscriberDeleted            = "store/subscriber/deleted"
+	ScopeChannelCreated               = "store/channel/created"
+	ScopeChannelUpdated               = "store/channel/updated"
+	ScopeStoreInformationUpdated      = "store/information/updated"
+	ScopeAppUninstalled               = "store/app/uninstalled"
+)
+
+// WebhookMeta holds the envelope fields common to every webhook delivery
+type WebhookMeta struct {
+	Scope     string
+	StoreID   string
+	Producer  string // "stores/{store_hash}"
+	Hash      string
+	CreatedAt time.Time
+}
+
+// StoreHash returns the store hash from the producer field
+func (m WebhookMeta) StoreHash() string {
+	return strings.TrimPrefix(m.Producer, "stores/")
+}
+
+// WebhookEvent is a webhook delivery with its scope specific data left undecoded
+// Use Decode or one of the typed WebhookRouter.On... methods to read the data.
+type WebhookEvent struct {
+	WebhookMeta
+	Data json.RawMessage
+	// Raw is the request body as received
+	Raw []byte
+	// Resource is the resource referenced by the event (*Order, *Product, *Customer or *Cart)
+	// when it was fetched by WebhookEnricher, nil otherwise
+	Resource interface{}
+}
+
+// ParseWebhookEvent decodes a webhook request body
+func ParseWebhookEvent(body []byte) (*WebhookEvent, error) {
+	var env struct {
+		Scope     string          `json:"scope"`
+		StoreID   string          `json:"store_id"`
+		Data      json.RawMessage `json:"data"`
+		Hash      string          `json:"hash"`
+		CreatedAt int64           `json:"created_at"`
+		Producer  string          `json:"producer"`
+	}
+	err := json.Unmarshal(body, &env)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookEvent{
+		WebhookMeta: WebhookMeta{
+			Scope:     env.Scope,
+			StoreID:   env.StoreID,
+			Producer:  env.Producer,
+			Hash:      env.Hash,
+			CreatedAt: time.Unix(env.CreatedAt, 0),
+		},
+		Data: env.Data,
+		Raw:  body,
+	}, nil
+}
+
+// Decode unmarshals the scope specific data of the event into v
+func (e *WebhookEvent) Decode(v interface{}) error {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// Payload returns the event as the catch-all WebhookPayload
+func (e *WebhookEvent) Payload() (*WebhookPayload, error) {
+	var p WebhookPayload
+	err := json.Unmarshal(e.Raw, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// webhookID is a resource ID that BigCommerce sends as number or string depending on scope
+type webhookID string
+
+func (id *webhookID) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*id = webhookID(s)
+		return nil
+	}
+	var n json.Number
+	err := json.Unmarshal(b, &n)
+	if err != nil {
+		return err
+	}
+	*id = webhookID(n.String())
+	return nil
+}
+
+func (id webhookID) int64() int64 {
+	n, _ := strconv.ParseInt(string(id), 10, 64)
+	return n
+}
+
+// webhookData is the union of the data fields of all scopes
+type webhookData struct {
+	Type     string    `json:"type"`
+	ID       webhookID `json:"id"`
+	CouponID webhookID `json:"couponId"`
+	CartID   webhookID `json:"cartId"`
+	OrderID  webhookID `json:"orderId"`
+	Address  struct {
+		CustomerID int64 `json:"customer_id"`
+	} `json:"address"`
+	Inventory InventoryEntry `json:"inventory"`
+	Message   struct {
+		OrderMessageID int64 `json:"order_message_id"`
+	} `json:"message"`
+	Refund struct {
+		RefundID int64 `json:"refund_id"`
+	} `json:"refund"`
+	Sku struct {
+		ProductID int64 `json:"product_id"`
+		VariantID int64 `json:"variant_id"`
+	} `json:"sku"`
+	Status struct {
+		PreviousStatusID OrderStatus `json:"previous_status_id"`
+		NewStatusID      OrderStatus `json:"new_status_id"`
+	} `json:"status"`
+}
+
+// skuProductID returns the product of a sku event, store/sku/inventory/* payloads carry it under inventory
+func (d *webhookData) skuProductID() int64 {
+	if d.Sku.ProductID != 0 {
+		return d.Sku.ProductID
+	}
+	return d.Inventory.ProductID
+}
+
+// skuVariantID returns the variant of a sku event, store/sku/inventory/* payloads carry it under inventory
+func (d *webhookData) skuVariantID() int64 {
+	if d.Sku.VariantID != 0 {
+		return d.Sku.VariantID
+	}
+	return d.Inventory.VariantID
+}
+
+func (e *WebhookEvent) order() *Order {
+	o, _ := e.Resource.(*Order)
+	return o
+}
+
+func (e *WebhookEvent) product() *Product {
+	p, _ := e.Resource.(*Product)
+	return p
+}
+
+func (e *WebhookEvent) customer() *Customer {
+	c, _ := e.Resource.(*Customer)
+	return c
+}
+
+func (e *WebhookEvent) cart() *Cart {
+	c, _ := e.Resource.(*Cart)
+	return c
+}
+
+func (e *WebhookEvent) data() (webhookData, error) {
+	var d webhookData
+	err := e.Decode(&d)
+	return d, err
+}
+
+// OrderEvent is sent for store/order/created, updated and archived
+type OrderEvent struct {
+	WebhookMeta
+	OrderID int64
+	Order   *Order
+}
+
+// OrderStatusUpdatedEvent is sent for store/order/statusUpdated
+type OrderStatusUpdatedEvent struct {
+	WebhookMeta
+	OrderID        int64
+	PreviousStatus OrderStatus
+	NewStatus      OrderStatus
+	Order          *Order
+}
+
+// OrderMessageCreatedEvent is sent for store/order/message/created
+type OrderMessageCreatedEvent struct {
+	WebhookMeta
+	OrderID   int64
+	MessageID int64
+	Order     *Order
+}
+
+// OrderRefundCreatedEvent is sent for store/order/refund/created
+type OrderRefundCreatedEvent struct {
+	WebhookMeta
+	OrderID  int64
+	RefundID int64
+	Order    *Order
+}
+
+// ProductEvent is sent for store/product/created, updated and deleted
+type ProductEvent struct {
+	WebhookMeta
+	ProductID int64
+	Product   *Product
+}
+
+// ProductInventoryEvent is sent for store/product/inventory/updated and inventory/order/updated
+type ProductInventoryEvent struct {
+	WebhookMeta
+	ProductID int64
+	Inventory InventoryEntry
+	Product   *Product
+}
+
+// CategoryEvent is sent for store/category/created, updated and deleted
+type CategoryEvent struct {
+	WebhookMeta
+	CategoryID int64
+}
+
+// SKUEvent is sent for store/sku/created, updated and deleted
+type SKUEvent struct {
+	WebhookMeta
+	SKUID     int64
+	ProductID int64
+	VariantID int64
+	Product   *Product
+}
+
+// SKUInventoryEvent is sent for store/sku/inventory/updated and inventory/order/updated
+type SKUInventoryEvent struct {
+	WebhookMeta
+	SKUID     int64
+	ProductID int64
+	VariantID int64
+	Inventory InventoryEntry
+	Product   *Product
+}
+
+// CustomerEvent is sent for store/customer/created, updated and deleted
+type CustomerEvent struct {
+	WebhookMeta
+	CustomerID int64
+	Customer   *Customer
+}
+
+// CustomerAddressEvent is sent for store/customer/address/created, updated and deleted
+type CustomerAddressEvent struct {
+	WebhookMeta
+	AddressID  int64
+	CustomerID int64
+	Customer   *Customer
+}
+
+// CartEvent is sent for store/cart/created, updated, deleted, abandoned and lineItem events
+type CartEvent struct {
+	WebhookMeta
+	CartID string
+	Cart   *Cart
+}
+
+// CartCouponAppliedEvent is sent for store/cart/couponApplied
+type CartCouponAppliedEvent struct {
+	WebhookMeta
+	CartID   string
+	CouponID string
+	Cart     *Cart
+}
+
+// CartConvertedEvent is sent for store/cart/converted
+type CartConvertedEvent struct {
+	WebhookMeta
+	CartID  string
+	OrderID int64
+	Order   *Order
+}
+
+// ShipmentEvent is sent for store/shipment/created, updated and deleted
+type ShipmentEvent struct {
+	WebhookMeta
+	ShipmentID int64
+	OrderID    int64
+}
+
+// OrderEvent decodes the event as an OrderEvent
+func (e *WebhookEvent) OrderEvent() (OrderEvent, error) {
+	d, err := e.data()
+	return OrderEvent{WebhookMeta: e.WebhookMeta, OrderID: d.ID.int64(), Order: e.order()}, err
+}
+
+// OrderStatusUpdatedEvent decodes the event as an OrderStatusUpdatedEvent
+func (e *WebhookEvent) OrderStatusUpdatedEvent() (OrderStatusUpdatedEvent, error) {
+	d, err := e.data()
+	return OrderStatusUpdatedEvent{
+		WebhookMeta:    e.WebhookMeta,
+		OrderID:        d.ID.int64(),
+		PreviousStatus: d.Status.PreviousStatusID,
+		NewStatus:      d.Status.NewStatusID,
+		Order:          e.order(),
+	}, err
+}
+
+// OrderMessageCreatedEvent decodes the event as an OrderMessageCreatedEvent
+func (e *WebhookEvent) OrderMessageCreatedEvent() (OrderMessageCreatedEvent, error) {
+	d, err := e.data()
+	return OrderMessageCreatedEvent{
+		WebhookMeta: e.WebhookMeta,
+		OrderID:     d.ID.int64(),
+		MessageID:   d.Message.OrderMessageID,
+		Order:       e.order(),
+	}, err
+}
+
+// OrderRefundCreatedEvent decodes the event as an OrderRefundCreatedEvent
+func (e *WebhookEvent) OrderRefundCreatedEvent() (OrderRefundCreatedEvent, error) {
+	d, err := e.data()
+	return OrderRefundCreatedEvent{
+		WebhookMeta: e.WebhookMeta,
+		OrderID:     d.ID.int64(),
+		RefundID:    d.Refund.RefundID,
+		Order:       e.order(),
+	}, err
+}
+
+// ProductEvent decodes the event as a ProductEvent
+func (e *WebhookEvent) ProductEvent() (ProductEvent, error) {
+	d, err := e.data()
+	return ProductEvent{WebhookMeta: e.WebhookMeta, ProductID: d.ID.int64(), Product: e.product()}, err
+}
+
+// ProductInventoryEvent decodes the event as a ProductInventoryEvent
+func (e *WebhookEvent) ProductInventoryEvent() (ProductInventoryEvent, error) {
+	d, err := e.data()
+	return ProductInventoryEvent{
+		WebhookMeta: e.WebhookMeta,
+		ProductID:   d.ID.int64(),
+		Inventory:   d.Inventory,
+		Product:     e.product(),
+	}, err
+}
+
+// CategoryEvent decodes the event as a CategoryEvent
+func (e *WebhookEvent) CategoryEvent() (CategoryEvent, error) {
+	d, err := e.data()
+	return CategoryEvent{WebhookMeta: e.WebhookMeta, CategoryID: d.ID.int64()}, err
+}
+
+// SKUEvent decodes the event as a SKUEvent
+func (e *WebhookEvent) SKUEvent() (SKUEvent, error) {
+	d, err := e.data()
+	return SKUEvent{
+		WebhookMeta: e.WebhookMeta,
+		SKUID:       d.ID.int64(),
+		ProductID:   d.skuProductID(),
+		VariantID:   d.skuVariantID(),
+		Product:     e.product(),
+	}, err
+}
+
+// SKUInventoryEvent decodes the event as a SKUInventoryEvent
+func (e *WebhookEvent) SKUInventoryEvent() (SKUInventoryEvent, error) {
+	d, err := e.data()
+	return SKUInventoryEvent{
+		WebhookMeta: e.WebhookMeta,
+		SKUID:       d.ID.int64(),
+		ProductID:   d.skuProductID(),
+		VariantID:   d.skuVariantID(),
+		Inventory:   d.Inventory,
+		Product:     e.product(),
+	}, err
+}
+
+// CustomerEvent decodes the event as a CustomerEvent
+func (e *WebhookEvent) CustomerEvent() (CustomerEvent, error) {
+	d, err := e.data()
+	return CustomerEvent{WebhookMeta: e.WebhookMeta, CustomerID: d.ID.int64(), Customer: e.customer()}, err
+}
+
+// CustomerAddressEvent decodes the event as a CustomerAddressEvent
+func (e *WebhookEvent) CustomerAddressEvent() (CustomerAddressEvent, error) {
+	d, err := e.data()
+	return CustomerAddressEvent{
+		WebhookMeta: e.WebhookMeta,
+		AddressID:   d.ID.int64(),
+		CustomerID:  d.Address.CustomerID,
+		Customer:    e.customer(),
+	}, err
+}
+
+// CartEvent decodes the event as a CartEvent
+// lineItem events carry the cart ID in cartId, all other cart events in id
+func (e *WebhookEvent) CartEvent() (CartEvent, error) {
+	d, err := e.data()
+	cartID := string(d.CartID)
+	if cartID == "" {
+		cartID = string(d.ID)
+	}
+	return CartEvent{WebhookMeta: e.WebhookMeta, CartID: cartID, Cart: e.cart()}, err
+}
+
+// CartCouponAppliedEvent decodes the event as a CartCouponAppliedEvent
+func (e *WebhookEvent) CartCouponAppliedEvent() (CartCouponAppliedEvent, error) {
+	d, err := e.data()
+	return CartCouponAppliedEvent{
+		WebhookMeta: e.WebhookMeta,
+		CartID:      string(d.ID),
+		CouponID:    string(d.CouponID),
+		Cart:        e.cart(),
+	}, err
+}
+
+// CartConvertedEvent decodes the event as a CartConvertedEvent
+func (e *WebhookEvent) CartConvertedEvent() (CartConvertedEvent, error) {
+	d, err := e.data()
+	return CartConvertedEvent{
+		WebhookMeta: e.WebhookMeta,
+		CartID:      string(d.ID),
+		OrderID:     d.OrderID.int64(),
+		Order:       e.order(),
+	}, err
+}
+
+// ShipmentEvent decodes the event as a ShipmentEvent
+func (e *WebhookEvent) ShipmentEvent() (ShipmentEvent, error) {
+	d, err := e.data()
+	return ShipmentEvent{
+		WebhookMeta: e.WebhookMeta,
+		ShipmentID:  d.ID.int64(),
+		OrderID:     d.OrderID.int64(),
+	}, err
+}
@@ -0,0 +1,37 @@
+package bigcommerce
+
+import "testing"
+
+func TestSKUInventoryEvent(t *testing.T) {
+	body := []byte(`{
+		"created_at": 1561482670,
+		"store_id": "1025646",
+		"producer": "stores/abc123",
+		"scope": "store/sku/inventory/updated",
+		"hash": "9b7ef9b4b3c1a5fd3e3d2b4bbf0e6d1f3c2c8c3e",
+		"data": {
+			"type": "sku",
+			"id": 461,
+			"inventory": {
+				"product_id": 206,
+				"method": "absolute",
+				"value": 5,
+				"variant_id": 509
+			}
+		}
+	}`)
+	e, err := ParseWebhookEvent(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := e.SKUInventoryEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.SKUID != 461 || ev.ProductID != 206 || ev.VariantID != 509 {
+		t.Errorf("got SKUID %d ProductID %d VariantID %d", ev.SKUID, ev.ProductID, ev.VariantID)
+	}
+	if ev.StoreHash() != "abc123" {
+		t.Errorf("got store hash %q", ev.StoreHash())
+	}
+}
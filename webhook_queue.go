@@ -0,0 +1,287 @@
+package bigcommerce
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned by ChannelPublisher when its buffer is full
+var ErrQueueFull = errors.New("webhook queue full")
+
+// EventPublisher hands webhook events over to asynchronous processing
+type EventPublisher interface {
+	Publish(ctx context.Context, e *WebhookEvent) error
+}
+
+// WebhookSink returns a WebhookHandler that publishes events instead of processing them,
+// so the receiver can acknowledge deliveries fast. A publish error makes BigCommerce retry.
+// Use it behind the verification and de-duplication middleware:
+//
+//	receiver := bigcommerce.NewWebhookRouter()
+//	receiver.Use(verifier.Middleware())
+//	receiver.Handle("*", bigcommerce.WebhookSink(publisher))
+func WebhookSink(p EventPublisher) WebhookHandler {
+	return WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) error {
+		return p.Publish(ctx, e)
+	})
+}
+
+// ChannelPublisher is an in-process EventPublisher backed by a buffered channel
+type ChannelPublisher struct {
+	events chan *WebhookEvent
+}
+
+// NewChannelPublisher returns a ChannelPublisher buffering up to size events
+func NewChannelPublisher(size int) *ChannelPublisher {
+	return &ChannelPublisher{
+		events: make(chan *WebhookEvent, size),
+	}
+}
+
+// Publish implements EventPublisher, it returns ErrQueueFull instead of blocking
+func (p *ChannelPublisher) Publish(ctx context.Context, e *WebhookEvent) error {
+	select {
+	case p.events <- e:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Events returns the channel to consume events from, see WebhookWorker.Run
+func (p *ChannelPublisher) Events() <-chan *WebhookEvent {
+	return p.events
+}
+
+// Close closes the events channel, Publish must not be called afterwards
+func (p *ChannelPublisher) Close() {
+	close(p.events)
+}
+
+// spoolEntry is a line of a spool file
+type spoolEntry struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Attempts   int             `json:"attempts,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// SpoolPublisher is an EventPublisher that appends events to a JSON Lines file
+// Events survive restarts of the process; call Drain from a worker to process them.
+// It can also be used as dead-letter sink of a WebhookWorker, preferably with its own file;
+// when a spool is its own dead-letter sink, Drain keeps the dead-lettered entries in the spool
+// without processing them again.
+type SpoolPublisher struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+}
+
+// NewSpoolPublisher opens or creates the spool file at path
+func NewSpoolPublisher(path string) (*SpoolPublisher, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &SpoolPublisher{path: path, f: f}, nil
+}
+
+// Publish implements EventPublisher, the event is synced to disk before it returns
+func (p *SpoolPublisher) Publish(ctx context.Context, e *WebhookEvent) error {
+	return p.write(spoolEntry{ReceivedAt: time.Now(), Payload: e.Raw})
+}
+
+func (p *SpoolPublisher) write(entry spoolEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.f == nil {
+		return os.ErrClosed
+	}
+	_, err = p.f.Write(b)
+	if err != nil {
+		return err
+	}
+	return p.f.Sync()
+}
+
+// Close closes the spool file
+func (p *SpoolPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.f == nil {
+		return nil
+	}
+	err := p.f.Close()
+	p.f = nil
+	return err
+}
+
+// Drain moves the spooled events aside, starts a new spool file and processes the events
+// with the worker. A drain interrupted by a crash or ctx is resumed by the next Drain,
+// so events are processed at least once. Entries dead-lettered after w.MaxAttempts attempts
+// are written back to the spool unprocessed.
+func (p *SpoolPublisher) Drain(ctx context.Context, w *WebhookWorker) error {
+	processing := p.path + ".processing"
+	if _, err := os.Stat(processing); os.IsNotExist(err) {
+		p.mu.Lock()
+		if p.f == nil {
+			p.mu.Unlock()
+			return os.ErrClosed
+		}
+		err = p.f.Close()
+		if err == nil {
+			err = os.Rename(p.path, processing)
+		}
+		f, ferr := os.OpenFile(p.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		p.f = f
+		p.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		if ferr != nil {
+			return ferr
+		}
+	}
+
+	f, err := os.Open(processing)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			f.Close()
+			return ctx.Err()
+		}
+		var entry spoolEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			log.Printf("webhook spool %s: skipping invalid line: %v", processing, err)
+			continue
+		}
+		if entry.Attempts >= w.maxAttempts() {
+			err = p.write(entry)
+			if err != nil {
+				f.Close()
+				return err
+			}
+			continue
+		}
+		e, err := ParseWebhookEvent(entry.Payload)
+		if err != nil {
+			log.Printf("webhook spool %s: skipping invalid payload: %v", processing, err)
+			continue
+		}
+		w.Process(ctx, e)
+	}
+	err = scanner.Err()
+	f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(processing)
+}
+
+// WebhookWorker processes published events with retries
+// Events still failing after MaxAttempts are published to DeadLetter.
+// Use:
+//
+//	publisher := bigcommerce.NewChannelPublisher(1000)
+//	worker := &bigcommerce.WebhookWorker{Handler: router, MaxAttempts: 5, DeadLetter: spool}
+//	go worker.Run(ctx, publisher.Events())
+type WebhookWorker struct {
+	// Handler processes the events, usually a WebhookRouter with the typed handlers
+	Handler WebhookHandler
+	// MaxAttempts defaults to 1
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled after every attempt
+	Backoff time.Duration
+	// DeadLetter receives events that failed MaxAttempts times, they are logged and dropped if nil
+	DeadLetter EventPublisher
+}
+
+// Run processes events until the channel is closed or ctx is done
+func (w *WebhookWorker) Run(ctx context.Context, events <-chan *WebhookEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			w.Process(ctx, e)
+		}
+	}
+}
+
+// Process handles an event with retries and returns the last error if it was dead-lettered
+func (w *WebhookWorker) Process(ctx context.Context, e *WebhookEvent) error {
+	attempts := w.maxAttempts()
+	backoff := w.Backoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 && backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		err = w.handle(ctx, e)
+		if err == nil {
+			return nil
+		}
+	}
+	log.Printf("webhook %s %s failed %d times: %v", e.Scope, e.Hash, attempts, err)
+	if w.DeadLetter == nil {
+		return err
+	}
+	if sp, ok := w.DeadLetter.(*SpoolPublisher); ok {
+		derr := sp.write(spoolEntry{
+			ReceivedAt: time.Now(),
+			Attempts:   attempts,
+			Error:      err.Error(),
+			Payload:    e.Raw,
+		})
+		if derr != nil {
+			log.Printf("webhook %s %s dead letter: %v", e.Scope, e.Hash, derr)
+		}
+		return err
+	}
+	if derr := w.DeadLetter.Publish(ctx, e); derr != nil {
+		log.Printf("webhook %s %s dead letter: %v", e.Scope, e.Hash, derr)
+	}
+	return err
+}
+
+func (w *WebhookWorker) maxAttempts() int {
+	if w.MaxAttempts < 1 {
+		return 1
+	}
+	return w.MaxAttempts
+}
+
+// handle runs the handler, turning a panic into an error
+func (w *WebhookWorker) handle(ctx context.Context, e *WebhookEvent) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("webhook %s panic: %v", e.Scope, rec)
+		}
+	}()
+	return w.Handler.HandleWebhook(ctx, e)
+}
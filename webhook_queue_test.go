@@ -0,0 +1,42 @@
+package bigcommerce
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpoolDrainKeepsDeadLetters(t *testing.T) {
+	spool, err := NewSpoolPublisher(filepath.Join(t.TempDir(), "spool.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spool.Close()
+	calls := 0
+	w := &WebhookWorker{
+		Handler: WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) error {
+			calls++
+			return errors.New("failed")
+		}),
+		MaxAttempts: 2,
+		DeadLetter:  spool,
+	}
+	e, err := ParseWebhookEvent([]byte(`{"scope":"store/order/created","store_id":"1","producer":"stores/abc","hash":"h","created_at":1,"data":{"type":"order","id":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = spool.Publish(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		err = spool.Drain(context.Background(), w)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("dead-lettered event processed again: %d calls", calls)
+	}
+}
@@ -0,0 +1,372 @@
+package bigcommerce
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// WebhookHandler processes a webhook event
+// A returned error makes the receiver respond with 500 so BigCommerce retries the delivery.
+type WebhookHandler interface {
+	HandleWebhook(ctx context.Context, e *WebhookEvent) error
+}
+
+// WebhookHandlerFunc is a function adapter for WebhookHandler
+type WebhookHandlerFunc func(ctx context.Context, e *WebhookEvent) error
+
+// HandleWebhook calls f(ctx, e)
+func (f WebhookHandlerFunc) HandleWebhook(ctx context.Context, e *WebhookEvent) error {
+	return f(ctx, e)
+}
+
+// WebhookMiddleware wraps a WebhookHandler, e.g. to verify, de-duplicate or enrich events
+type WebhookMiddleware func(next WebhookHandler) WebhookHandler
+
+// WebhookRouter is an http.Handler that dispatches BigCommerce webhooks to handlers by scope
+// Scopes can be exact ("store/order/created") or wildcards ("store/product/*", "*").
+// It responds 200 when all handlers succeeded or no handler is registered for the scope,
+// 400 when the body is not a webhook payload and 500 when a handler failed or panicked,
+// so that BigCommerce retries the delivery.
+// Use:
+//
+//	router := bigcommerce.NewWebhookRouter()
+//	router.OnOrderStatusUpdated(func(ctx context.Context, e bigcommerce.OrderStatusUpdatedEvent) error {
+//		log.Printf("order %d is now %s", e.OrderID, e.NewStatus)
+//		return nil
+//	})
+//	http.Handle("/webhooks", router)
+type WebhookRouter struct {
+	mu         sync.RWMutex
+	handlers   map[string][]WebhookHandler
+	wildcards  []wildcardHandler
+	middleware []WebhookMiddleware
+}
+
+type wildcardHandler struct {
+	prefix  string
+	handler WebhookHandler
+}
+
+// NewWebhookRouter returns an empty WebhookRouter
+func NewWebhookRouter() *WebhookRouter {
+	return &WebhookRouter{
+		handlers: map[string][]WebhookHandler{},
+	}
+}
+
+// Use appends middleware to the handler chain, the first one added runs first
+func (wr *WebhookRouter) Use(mw ...WebhookMiddleware) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.middleware = append(wr.middleware, mw...)
+}
+
+// Handle registers a handler for a scope, several handlers can be registered for the same scope
+func (wr *WebhookRouter) Handle(scope string, h WebhookHandler) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	if scope == "*" || strings.HasSuffix(scope, "/*") {
+		wr.wildcards = append(wr.wildcards, wildcardHandler{
+			prefix:  strings.TrimSuffix(scope, "*"),
+			handler: h,
+		})
+		return
+	}
+	wr.handlers[scope] = append(wr.handlers[scope], h)
+}
+
+// HandleFunc registers a handler function for a scope
+func (wr *WebhookRouter) HandleFunc(scope string, f func(ctx context.Context, e *WebhookEvent) error) {
+	wr.Handle(scope, WebhookHandlerFunc(f))
+}
+
+// Match returns the handlers registered for a scope, exact matches first
+func (wr *WebhookRouter) Match(scope string) []WebhookHandler {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+	hs := append([]WebhookHandler{}, wr.handlers[scope]...)
+	for _, w := range wr.wildcards {
+		if strings.HasPrefix(scope, w.prefix) {
+			hs = append(hs, w.handler)
+		}
+	}
+	return hs
+}
+
+// HandleWebhook runs the middleware chain and the handlers matching the event scope
+// It implements WebhookHandler, so events received outside of ServeHTTP (e.g. from a queue)
+// can be dispatched too.
+func (wr *WebhookRouter) HandleWebhook(ctx context.Context, e *WebhookEvent) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("webhook %s panic: %v\n%s", e.Scope, rec, debug.Stack())
+			err = fmt.Errorf("webhook %s panic: %v", e.Scope, rec)
+		}
+	}()
+	wr.mu.RLock()
+	mw := wr.middleware
+	wr.mu.RUnlock()
+	var h WebhookHandler = WebhookHandlerFunc(wr.dispatch)
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h.HandleWebhook(ctx, e)
+}
+
+func (wr *WebhookRouter) dispatch(ctx context.Context, e *WebhookEvent) error {
+	for _, h := range wr.Match(e.Scope) {
+		err := h.HandleWebhook(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (wr *WebhookRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.Body.Close()
+	e, err := ParseWebhookEvent(body)
+	if err != nil || e.Scope == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = wr.HandleWebhook(withWebhookRequest(r.Context(), r), e)
+	if err != nil {
+		log.Printf("webhook %s %s: %v", e.Scope, e.Hash, err)
+		w.WriteHeader(webhookErrorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// WebhookError is an error with the HTTP status to respond to BigCommerce
+// Statuses below 500 are for deliveries that must not be processed (e.g. failed verification).
+type WebhookError struct {
+	Status int
+	Err    error
+}
+
+func (e *WebhookError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+func webhookErrorStatus(err error) int {
+	if we, ok := err.(*WebhookError); ok && we.Status != 0 {
+		return we.Status
+	}
+	return http.StatusInternalServerError
+}
+
+type webhookRequestKey struct{}
+
+func withWebhookRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, webhookRequestKey{}, r)
+}
+
+// WebhookRequest returns the HTTP request a webhook event was received with
+// it returns nil when the event was not dispatched by ServeHTTP
+func WebhookRequest(ctx context.Context) *http.Request {
+	r, _ := ctx.Value(webhookRequestKey{}).(*http.Request)
+	return r
+}
+
+// OnOrderCreated registers a handler for store/order/created
+func (wr *WebhookRouter) OnOrderCreated(f func(ctx context.Context, e OrderEvent) error) {
+	wr.onOrderEvent(ScopeOrderCreated, f)
+}
+
+// OnOrderUpdated registers a handler for store/order/updated
+func (wr *WebhookRouter) OnOrderUpdated(f func(ctx context.Context, e OrderEvent) error) {
+	wr.onOrderEvent(ScopeOrderUpdated, f)
+}
+
+// OnOrderArchived registers a handler for store/order/archived
+func (wr *WebhookRouter) OnOrderArchived(f func(ctx context.Context, e OrderEvent) error) {
+	wr.onOrderEvent(ScopeOrderArchived, f)
+}
+
+func (wr *WebhookRouter) onOrderEvent(scope string, f func(ctx context.Context, e OrderEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.OrderEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnOrderStatusUpdated registers a handler for store/order/statusUpdated
+func (wr *WebhookRouter) OnOrderStatusUpdated(f func(ctx context.Context, e OrderStatusUpdatedEvent) error) {
+	wr.HandleFunc(ScopeOrderStatusUpdated, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.OrderStatusUpdatedEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnOrderMessageCreated registers a handler for store/order/message/created
+func (wr *WebhookRouter) OnOrderMessageCreated(f func(ctx context.Context, e OrderMessageCreatedEvent) error) {
+	wr.HandleFunc(ScopeOrderMessageCreated, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.OrderMessageCreatedEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnOrderRefundCreated registers a handler for store/order/refund/created
+func (wr *WebhookRouter) OnOrderRefundCreated(f func(ctx context.Context, e OrderRefundCreatedEvent) error) {
+	wr.HandleFunc(ScopeOrderRefundCreated, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.OrderRefundCreatedEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnProduct registers a handler for store/product/created, updated or deleted
+func (wr *WebhookRouter) OnProduct(scope string, f func(ctx context.Context, e ProductEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.ProductEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnProductInventory registers a handler for store/product/inventory/updated or inventory/order/updated
+func (wr *WebhookRouter) OnProductInventory(scope string, f func(ctx context.Context, e ProductInventoryEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.ProductInventoryEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCategory registers a handler for store/category/created, updated or deleted
+func (wr *WebhookRouter) OnCategory(scope string, f func(ctx context.Context, e CategoryEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CategoryEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnSKU registers a handler for store/sku/created, updated or deleted
+func (wr *WebhookRouter) OnSKU(scope string, f func(ctx context.Context, e SKUEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.SKUEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnSKUInventory registers a handler for store/sku/inventory/updated or inventory/order/updated
+func (wr *WebhookRouter) OnSKUInventory(scope string, f func(ctx context.Context, e SKUInventoryEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.SKUInventoryEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCustomer registers a handler for store/customer/created, updated or deleted
+func (wr *WebhookRouter) OnCustomer(scope string, f func(ctx context.Context, e CustomerEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CustomerEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCustomerAddress registers a handler for store/customer/address/created, updated or deleted
+func (wr *WebhookRouter) OnCustomerAddress(scope string, f func(ctx context.Context, e CustomerAddressEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CustomerAddressEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCart registers a handler for store/cart/created, updated, deleted, abandoned or lineItem scopes
+func (wr *WebhookRouter) OnCart(scope string, f func(ctx context.Context, e CartEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CartEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCartCouponApplied registers a handler for store/cart/couponApplied
+func (wr *WebhookRouter) OnCartCouponApplied(f func(ctx context.Context, e CartCouponAppliedEvent) error) {
+	wr.HandleFunc(ScopeCartCouponApplied, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CartCouponAppliedEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnCartConverted registers a handler for store/cart/converted
+func (wr *WebhookRouter) OnCartConverted(f func(ctx context.Context, e CartConvertedEvent) error) {
+	wr.HandleFunc(ScopeCartConverted, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.CartConvertedEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
+
+// OnShipment registers a handler for store/shipment/created, updated or deleted
+func (wr *WebhookRouter) OnShipment(scope string, f func(ctx context.Context, e ShipmentEvent) error) {
+	wr.HandleFunc(scope, func(ctx context.Context, e *WebhookEvent) error {
+		ev, err := e.ShipmentEvent()
+		if err != nil {
+			return err
+		}
+		return f(ctx, ev)
+	})
+}
@@ -0,0 +1,117 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// fakeHooksClient returns a client backed by an in-memory /v3/hooks endpoint
+func fakeHooksClient(t *testing.T, hooks map[int64]*Webhook) *Client {
+	client := NewClient("store", "token")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		respond := func(v interface{}) (*http.Response, error) {
+			b, _ := json.Marshal(map[string]interface{}{"data": v, "meta": map[string]interface{}{}})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Status:     "200 OK",
+				Body:       ioutil.NopCloser(strings.NewReader(string(b))),
+				Request:    r,
+			}, nil
+		}
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/v3/hooks"):
+			list := []Webhook{}
+			for _, w := range hooks {
+				list = append(list, *w)
+			}
+			return respond(list)
+		case r.Method == http.MethodPut:
+			var update map[string]json.RawMessage
+			err := json.NewDecoder(r.Body).Decode(&update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var w *Webhook
+			for _, h := range hooks {
+				if strings.HasSuffix(r.URL.Path, "/v3/hooks/"+strconv.FormatInt(h.ID, 10)) {
+					w = h
+				}
+			}
+			if w == nil {
+				t.Fatalf("unexpected update of %s", r.URL.Path)
+			}
+			if raw, ok := update["headers"]; ok {
+				w.Headers = nil
+				json.Unmarshal(raw, &w.Headers)
+			}
+			if raw, ok := update["destination"]; ok {
+				json.Unmarshal(raw, &w.Destination)
+			}
+			if raw, ok := update["is_active"]; ok {
+				json.Unmarshal(raw, &w.IsActive)
+			}
+			return respond(w)
+		}
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL)
+		return nil, nil
+	})}
+	return client
+}
+
+func TestWebhookUpdateHeaders(t *testing.T) {
+	active := true
+	tests := []struct {
+		update WebhookUpdate
+		want   string
+	}{
+		{WebhookUpdate{IsActive: &active}, `{"is_active":true}`},
+		{WebhookUpdate{Headers: map[string]string{}}, `{"headers":{}}`},
+		{WebhookUpdate{Headers: map[string]string{"X-Secret": "s"}}, `{"headers":{"X-Secret":"s"}}`},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.update)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("got %s, want %s", b, tt.want)
+		}
+	}
+}
+
+func TestEnsureWebhooksRemovesHeaders(t *testing.T) {
+	hooks := map[int64]*Webhook{
+		1: {ID: 1, Scope: ScopeOrderCreated, Destination: "https://app/hooks", IsActive: true, Headers: map[string]string{"X-Secret": "old"}},
+	}
+	client := fakeHooksClient(t, hooks)
+	desired := []WebhookSpec{{Scope: ScopeOrderCreated, Destination: "https://app/hooks"}}
+
+	diff, err := client.EnsureWebhooks(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Updated) != 1 {
+		t.Fatalf("expected 1 updated hook, got %+v", diff)
+	}
+	if len(hooks[1].Headers) != 0 {
+		t.Fatalf("headers not removed: %v", hooks[1].Headers)
+	}
+
+	diff, err = client.EnsureWebhooks(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.Changed() || len(diff.Unchanged) != 1 {
+		t.Fatalf("expected no changes on the second run, got %+v", diff)
+	}
+}
@@ -0,0 +1,132 @@
+package bigcommerce
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// DefaultWebhookSecretHeader is the header name used by WebhookVerifier when none is set
+const DefaultWebhookSecretHeader = "X-Webhook-Secret"
+
+// ErrWebhookUnauthorized is returned when a webhook delivery doesn't carry a valid secret
+var ErrWebhookUnauthorized = errors.New("webhook secret mismatch")
+
+// WebhookVerifier checks the shared secret header that BigCommerce sends with every delivery
+// of a webhook created with custom headers (see Headers and CreateWebhook).
+// Two secrets can be active at the same time to rotate them without dropping deliveries:
+// call Rotate with the new secret, update the webhooks with the new Headers, then call
+// Rotate again or SetSecrets(new, "") once all hooks were updated.
+type WebhookVerifier struct {
+	Header string
+
+	mu       sync.RWMutex
+	current  string
+	previous string
+}
+
+// NewWebhookVerifier returns a WebhookVerifier for the given header name and secret
+// header defaults to DefaultWebhookSecretHeader when empty
+func NewWebhookVerifier(header, secret string) *WebhookVerifier {
+	if header == "" {
+		header = DefaultWebhookSecretHeader
+	}
+	return &WebhookVerifier{
+		Header:  header,
+		current: secret,
+	}
+}
+
+// SetSecrets sets the current and previous secret, previous can be empty
+func (v *WebhookVerifier) SetSecrets(current, previous string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.current = current
+	v.previous = previous
+}
+
+// Rotate makes secret the current secret and keeps the old current secret active as previous
+func (v *WebhookVerifier) Rotate(secret string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.previous = v.current
+	v.current = secret
+}
+
+// Headers returns the custom headers to register webhooks with, using the current secret
+func (v *WebhookVerifier) Headers() map[string]string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return map[string]string{v.header(): v.current}
+}
+
+func (v *WebhookVerifier) header() string {
+	if v.Header == "" {
+		return DefaultWebhookSecretHeader
+	}
+	return v.Header
+}
+
+// Verify returns ErrWebhookUnauthorized if the request doesn't carry one of the active secrets
+func (v *WebhookVerifier) Verify(r *http.Request) error {
+	v.mu.RLock()
+	current, previous := v.current, v.previous
+	v.mu.RUnlock()
+	if current == "" && previous == "" {
+		return errors.New("webhook verifier has no secret")
+	}
+	got := []byte(r.Header.Get(v.header()))
+	// compare against both secrets so the timing doesn't tell which one matched
+	match := 0
+	if current != "" {
+		match |= subtle.ConstantTimeCompare(got, []byte(current))
+	}
+	if previous != "" {
+		match |= subtle.ConstantTimeCompare(got, []byte(previous))
+	}
+	if match != 1 {
+		return ErrWebhookUnauthorized
+	}
+	return nil
+}
+
+// GetWebhookPayload verifies the request and returns its WebhookPayload and raw body
+// see GetWebhookPayload
+func (v *WebhookVerifier) GetWebhookPayload(r *http.Request) (*WebhookPayload, []byte, error) {
+	err := v.Verify(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return GetWebhookPayload(r)
+}
+
+// Handler is an http middleware that responds 401 to unverified requests
+func (v *WebhookVerifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v.Verify(r) != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Middleware returns a WebhookMiddleware for WebhookRouter.Use that rejects unverified
+// deliveries with 401 before any handler runs
+// Events dispatched without an HTTP request (e.g. from a queue) are passed through.
+func (v *WebhookVerifier) Middleware() WebhookMiddleware {
+	return func(next WebhookHandler) WebhookHandler {
+		return WebhookHandlerFunc(func(ctx context.Context, e *WebhookEvent) error {
+			r := WebhookRequest(ctx)
+			if r != nil {
+				err := v.Verify(r)
+				if err != nil {
+					return &WebhookError{Status: http.StatusUnauthorized, Err: err}
+				}
+			}
+			return next.HandleWebhook(ctx, e)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+// Package webhooktest generates and delivers BigCommerce webhook payloads for testing
+// webhook receivers without a live store or tunnel.
+//
+// Use:
+//
+//	sim := webhooktest.New("abc123", router)
+//	sim.Headers = verifier.Headers()
+//	status, err := sim.Send(bigcommerce.ScopeOrderStatusUpdated, webhooktest.OrderStatusUpdated(100, 11, 2))
+package webhooktest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gpmd/bigcommerce-api-go"
+)
+
+// Simulator builds webhook payloads for a store and delivers them to a handler or URL
+type Simulator struct {
+	StoreHash string
+	StoreID   string
+	// Headers are sent with every delivery, e.g. WebhookVerifier.Headers()
+	Headers map[string]string
+	// Handler receives the deliveries in-process, URL is used when Handler is nil
+	Handler http.Handler
+	URL     string
+	// HTTPClient is used for deliveries to URL, defaults to http.DefaultClient
+	HTTPClient *http.Client
+	// Now returns the created_at time of generated payloads, defaults to time.Now
+	Now func() time.Time
+
+	seq int64
+}
+
+// New returns a Simulator delivering to handler
+func New(storeHash string, handler http.Handler) *Simulator {
+	return &Simulator{
+		StoreHash: storeHash,
+		StoreID:   "1001",
+		Handler:   handler,
+	}
+}
+
+// NewForURL returns a Simulator delivering over HTTP to url
+func NewForURL(storeHash, url string) *Simulator {
+	return &Simulator{
+		StoreHash: storeHash,
+		StoreID:   "1001",
+		URL:       url,
+	}
+}
+
+// Payload returns a webhook body with the envelope BigCommerce sends:
+// scope, store_id, producer, created_at and a hash of the data
+// data is encoded as the "data" field; use DataFor for the documented default of a scope.
+func (s *Simulator) Payload(scope string, data interface{}) ([]byte, error) {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	created := now().Unix()
+	// the hash differs for every generated payload, like separate events of a live store
+	h := sha1.Sum([]byte(fmt.Sprintf("%s|%d|%d|%s", scope, created, atomic.AddInt64(&s.seq, 1), d)))
+	return json.Marshal(map[string]interface{}{
+		"scope":      scope,
+		"store_id":   s.StoreID,
+		"data":       json.RawMessage(d),
+		"hash":       hex.EncodeToString(h[:]),
+		"created_at": created,
+		"producer":   "stores/" + s.StoreHash,
+	})
+}
+
+// Send generates a payload and delivers it, returning the response status
+func (s *Simulator) Send(scope string, data interface{}) (int, error) {
+	body, err := s.Payload(scope, data)
+	if err != nil {
+		return 0, err
+	}
+	return s.Deliver(body)
+}
+
+// SendScope delivers the documented default payload of a scope for the resource id
+func (s *Simulator) SendScope(scope string, id int64) (int, error) {
+	data, err := DataFor(scope, id)
+	if err != nil {
+		return 0, err
+	}
+	return s.Send(scope, data)
+}
+
+// Deliver posts a raw body with the configured headers, the way BigCommerce does
+func (s *Simulator) Deliver(body []byte) (int, error) {
+	if s.Handler != nil {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		s.setHeaders(req)
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, req)
+		return rec.Code, nil
+	}
+	if s.URL == "" {
+		return 0, fmt.Errorf("webhooktest: no Handler or URL to deliver to")
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	s.setHeaders(req)
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	return res.StatusCode, nil
+}
+
+func (s *Simulator) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Bigcommerce-Webhooks")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// Replay delivers recorded payloads from r, one JSON document per line
+// Lines can be raw webhook bodies or entries of a bigcommerce.SpoolPublisher file.
+// It returns the response status of every delivery in order.
+func (s *Simulator) Replay(r io.Reader) ([]int, error) {
+	statuses := []int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var spooled struct {
+			Payload json.RawMessage `json:"payload"`
+		}
+		body := append([]byte{}, line...)
+		if json.Unmarshal(line, &spooled) == nil && len(spooled.Payload) > 0 {
+			body = spooled.Payload
+		}
+		status, err := s.Deliver(body)
+		if err != nil {
+			return statuses, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, scanner.Err()
+}
+
+// ReplayFile delivers the recorded payloads of a file, see Replay
+func (s *Simulator) ReplayFile(path string) ([]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.Replay(f)
+}
+
+// Scopes are all scopes DataFor generates payloads for
+var Scopes = []string{
+	bigcommerce.ScopeOrderCreated,
+	bigcommerce.ScopeOrderUpdated,
+	bigcommerce.ScopeOrderArchived,
+	bigcommerce.ScopeOrderStatusUpdated,
+	bigcommerce.ScopeOrderMessageCreated,
+	bigcommerce.ScopeOrderRefundCreated,
+	bigcommerce.ScopeProductCreated,
+	bigcommerce.ScopeProductUpdated,
+	bigcommerce.ScopeProductDeleted,
+	bigcommerce.ScopeProductInventoryUpdated,
+	bigcommerce.ScopeProductInventoryOrderUpdated,
+	bigcommerce.ScopeCategoryCreated,
+	bigcommerce.ScopeCategoryUpdated,
+	bigcommerce.ScopeCategoryDeleted,
+	bigcommerce.ScopeSKUCreated,
+	bigcommerce.ScopeSKUUpdated,
+	bigcommerce.ScopeSKUDeleted,
+	bigcommerce.ScopeSKUInventoryUpdated,
+	bigcommerce.ScopeSKUInventoryOrderUpdated,
+	bigcommerce.ScopeCustomerCreated,
+	bigcommerce.ScopeCustomerUpdated,
+	bigcommerce.ScopeCustomerDeleted,
+	bigcommerce.ScopeCustomerAddressCreated,
+	bigcommerce.ScopeCustomerAddressUpdated,
+	bigcommerce.ScopeCustomerAddressDeleted,
+	bigcommerce.ScopeCartCreated,
+	bigcommerce.ScopeCartUpdated,
+	bigcommerce.ScopeCartDeleted,
+	bigcommerce.ScopeCartAbandoned,
+	bigcommerce.ScopeCartCouponApplied,
+	bigcommerce.ScopeCartConverted,
+	bigcommerce.ScopeCartLineItemCreated,
+	bigcommerce.ScopeCartLineItemUpdated,
+	bigcommerce.ScopeCartLineItemDeleted,
+	bigcommerce.ScopeShipmentCreated,
+	bigcommerce.ScopeShipmentUpdated,
+	bigcommerce.ScopeShipmentDeleted,
+	bigcommerce.ScopeSubscriberCreated,
+	bigcommerce.ScopeSubscriberUpdated,
+	bigcommerce.ScopeSubscriberDeleted,
+	bigcommerce.ScopeChannelCreated,
+	bigcommerce.ScopeChannelUpdated,
+	bigcommerce.ScopeStoreInformationUpdated,
+	bigcommerce.ScopeAppUninstalled,
+}
+
+// DataFor returns the documented data of a scope for the resource id
+// Cart scopes use a cart UUID derived from id, related IDs are derived from id as well.
+func DataFor(scope string, id int64) (map[string]interface{}, error) {
+	switch scope {
+	case bigcommerce.ScopeOrderCreated, bigcommerce.ScopeOrderUpdated, bigcommerce.ScopeOrderArchived:
+		return Order(id), nil
+	case bigcommerce.ScopeOrderStatusUpdated:
+		return OrderStatusUpdated(id, bigcommerce.OrderStatusPending, bigcommerce.OrderStatusAwaitingFulfillment), nil
+	case bigcommerce.ScopeOrderMessageCreated:
+		return OrderMessageCreated(id, id*10), nil
+	case bigcommerce.ScopeOrderRefundCreated:
+		return map[string]interface{}{"type": "order", "id": id, "refund": map[string]interface{}{"refund_id": id * 10}}, nil
+	case bigcommerce.ScopeProductCreated, bigcommerce.ScopeProductUpdated, bigcommerce.ScopeProductDeleted:
+		return map[string]interface{}{"type": "product", "id": id}, nil
+	case bigcommerce.ScopeProductInventoryUpdated, bigcommerce.ScopeProductInventoryOrderUpdated:
+		return map[string]interface{}{"type": "product", "id": id, "inventory": map[string]interface{}{
+			"product_id": id, "method": inventoryMethod(scope), "value": -1,
+		}}, nil
+	case bigcommerce.ScopeCategoryCreated, bigcommerce.ScopeCategoryUpdated, bigcommerce.ScopeCategoryDeleted:
+		return map[string]interface{}{"type": "category", "id": id}, nil
+	case bigcommerce.ScopeSKUCreated, bigcommerce.ScopeSKUUpdated, bigcommerce.ScopeSKUDeleted:
+		return map[string]interface{}{"type": "sku", "id": id, "sku": map[string]interface{}{
+			"product_id": id * 10, "variant_id": id * 100,
+		}}, nil
+	case bigcommerce.ScopeSKUInventoryUpdated, bigcommerce.ScopeSKUInventoryOrderUpdated:
+		return map[string]interface{}{"type": "sku", "id": id, "inventory": map[string]interface{}{
+			"product_id": id * 10, "variant_id": id * 100, "method": inventoryMethod(scope), "value": -1,
+		}}, nil
+	case bigcommerce.ScopeCustomerCreated, bigcommerce.ScopeCustomerUpdated, bigcommerce.ScopeCustomerDeleted:
+		return map[string]interface{}{"type": "customer", "id": id}, nil
+	case bigcommerce.ScopeCustomerAddressCreated, bigcommerce.ScopeCustomerAddressUpdated, bigcommerce.ScopeCustomerAddressDeleted:
+		return map[string]interface{}{"type": "customer", "id": id, "address": map[string]interface{}{"customer_id": id * 10}}, nil
+	case bigcommerce.ScopeCartCreated, bigcommerce.ScopeCartUpdated, bigcommerce.ScopeCartDeleted, bigcommerce.ScopeCartAbandoned:
+		return map[string]interface{}{"type": "cart", "id": CartID(id)}, nil
+	case bigcommerce.ScopeCartCouponApplied:
+		return map[string]interface{}{"type": "cart", "id": CartID(id), "couponId": id * 10}, nil
+	case bigcommerce.ScopeCartConverted:
+		return map[string]interface{}{"type": "cart", "id": CartID(id), "orderId": id * 10}, nil
+	case bigcommerce.ScopeCartLineItemCreated, bigcommerce.ScopeCartLineItemUpdated, bigcommerce.ScopeCartLineItemDeleted:
+		return map[string]interface{}{"type": "cart_line_item", "id": CartID(id * 10), "cartId": CartID(id)}, nil
+	case bigcommerce.ScopeShipmentCreated, bigcommerce.ScopeShipmentUpdated, bigcommerce.ScopeShipmentDeleted:
+		return map[string]interface{}{"type": "shipment", "id": id, "orderId": id * 10}, nil
+	case bigcommerce.ScopeSubscriberCreated, bigcommerce.ScopeSubscriberUpdated, bigcommerce.ScopeSubscriberDeleted:
+		return map[string]interface{}{"type": "subscriber", "id": id}, nil
+	case bigcommerce.ScopeChannelCreated, bigcommerce.ScopeChannelUpdated:
+		return map[string]interface{}{"type": "channel", "id": id}, nil
+	case bigcommerce.ScopeStoreInformationUpdated, bigcommerce.ScopeAppUninstalled:
+		return map[string]interface{}{"type": "store"}, nil
+	}
+	return nil, fmt.Errorf("webhooktest: unknown scope %q", scope)
+}
+
+func inventoryMethod(scope string) string {
+	if strings.Contains(scope, "/inventory/order/") {
+		return "relative"
+	}
+	return "absolute"
+}
+
+// Order returns the data of store/order/created, updated and archived
+func Order(orderID int64) map[string]interface{} {
+	return map[string]interface{}{"type": "order", "id": orderID}
+}
+
+// OrderStatusUpdated returns the data of store/order/statusUpdated
+func OrderStatusUpdated(orderID int64, previous, next bigcommerce.OrderStatus) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "order",
+		"id":   orderID,
+		"status": map[string]interface{}{
+			"previous_status_id": previous,
+			"new_status_id":      next,
+		},
+	}
+}
+
+// OrderMessageCreated returns the data of store/order/message/created
+func OrderMessageCreated(orderID, messageID int64) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "order",
+		"id":      orderID,
+		"message": map[string]interface{}{"order_message_id": messageID},
+	}
+}
+
+// CartID returns a stable cart UUID for n
+func CartID(n int64) string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", n>>16, n)
+}
@@ -0,0 +1,120 @@
+package webhooktest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gpmd/bigcommerce-api-go"
+)
+
+func TestPayloadRoundTrip(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	router := bigcommerce.NewWebhookRouter()
+	var received []*bigcommerce.WebhookEvent
+	for _, scope := range Scopes {
+		router.HandleFunc(scope, func(ctx context.Context, e *bigcommerce.WebhookEvent) error {
+			received = append(received, e)
+			return nil
+		})
+	}
+	sim := New("abc123", router)
+	sim.Now = func() time.Time { return created }
+
+	for _, scope := range Scopes {
+		data, err := DataFor(scope, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := sim.Payload(scope, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := bigcommerce.ParseWebhookEvent(body)
+		if err != nil {
+			t.Fatalf("%s: %v", scope, err)
+		}
+		if e.Scope != scope || e.StoreHash() != "abc123" || e.StoreID != sim.StoreID || e.Hash == "" || !e.CreatedAt.Equal(created) {
+			t.Errorf("%s: unexpected envelope %+v", scope, e.WebhookMeta)
+		}
+		var d struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(e.Data, &d); err != nil || d.Type == "" {
+			t.Errorf("%s: data without type: %s", scope, e.Data)
+		}
+
+		status, err := sim.Deliver(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != 200 {
+			t.Errorf("%s: router answered %d", scope, status)
+		}
+	}
+	if len(received) != len(Scopes) {
+		t.Fatalf("router handled %d of %d events", len(received), len(Scopes))
+	}
+	for i, e := range received {
+		if e.Scope != Scopes[i] {
+			t.Errorf("event %d: got scope %s, want %s", i, e.Scope, Scopes[i])
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+	router := bigcommerce.NewWebhookRouter()
+	var ids []string
+	router.HandleFunc(bigcommerce.ScopeOrderCreated, func(ctx context.Context, e *bigcommerce.WebhookEvent) error {
+		var d struct {
+			ID json.Number `json:"id"`
+		}
+		json.Unmarshal(e.Data, &d)
+		ids = append(ids, d.ID.String())
+		return nil
+	})
+	sim := New("abc123", router)
+
+	raw, err := sim.Payload(bigcommerce.ScopeOrderCreated, Order(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spooled, err := sim.Payload(bigcommerce.ScopeOrderCreated, Order(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := bigcommerce.ParseWebhookEvent(spooled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	spool, err := bigcommerce.NewSpoolPublisher(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = spool.Publish(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spool.Close()
+	spoolLine, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recording := string(raw) + "\n\n" + string(spoolLine)
+	statuses, err := sim.Replay(strings.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0] != 200 || statuses[1] != 200 {
+		t.Fatalf("unexpected statuses %v", statuses)
+	}
+	if strings.Join(ids, ",") != "1,2" {
+		t.Errorf("replayed orders %v, want [1 2]", ids)
+	}
+}